@@ -0,0 +1,142 @@
+package syncsign
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	events "github.com/tommzn/hdb-events-go"
+)
+
+// toAggregationStrategy converts passed config value into an aggregation strategy. Defaults to latest value.
+func toAggregationStrategy(strategy string) aggregationStrategy {
+
+	switch aggregation := aggregationStrategy(strings.ToLower(strategy)); aggregation {
+	case AGGREGATION_AVERAGE, AGGREGATION_MIN, AGGREGATION_MAX, AGGREGATION_PRIMARY:
+		return aggregation
+	default:
+		return AGGREGATION_LATEST
+	}
+}
+
+// addDeviceValue keeps passed value as latest value of it's measurement type for given device.
+// Caller has to hold the lock.
+func (renderer *IndoorClimateRenderer) addDeviceValue(measurementType events.MeasurementType, value deviceValue) {
+
+	if _, ok := renderer.deviceValues[value.deviceId]; !ok {
+		renderer.deviceValues[value.deviceId] = make(map[events.MeasurementType]deviceValue)
+	}
+	renderer.deviceValues[value.deviceId][measurementType] = value
+}
+
+// aggregatedValue combines latest values of all devices in a room for passed measurement type, using
+// aggregation strategy of the room. Battery is always taken from the weakest device. Values which exceed
+// max age of their measurement type at given time are skipped as long as there're other values.
+// Caller has to hold the lock.
+func (renderer *IndoorClimateRenderer) aggregatedValue(roomId string, measurementType events.MeasurementType, now time.Time) deviceValue {
+
+	values := renderer.roomDeviceValues(roomId, measurementType, now)
+	if measurementType == events.MeasurementType_BATTERY {
+		return renderer.weakestBattery(values)
+	}
+
+	roomCfg := renderer.roomCfg.rooms[roomId]
+	switch roomCfg.aggregation {
+	case AGGREGATION_PRIMARY:
+		for _, value := range values {
+			if value.deviceId == roomCfg.primaryDevice {
+				return value
+			}
+		}
+		return latestValue(values)
+	case AGGREGATION_AVERAGE, AGGREGATION_MIN, AGGREGATION_MAX:
+		if aggregated, ok := aggregateNumbers(values, roomCfg.aggregation); ok {
+			return aggregated
+		}
+		return latestValue(values)
+	default:
+		return latestValue(values)
+	}
+}
+
+// roomDeviceValues returns latest values of passed measurement type for all devices assigned to given room.
+// Expired values are dropped, unless all values are expired. Caller has to hold the lock.
+func (renderer *IndoorClimateRenderer) roomDeviceValues(roomId string, measurementType events.MeasurementType, now time.Time) []deviceValue {
+
+	values := []deviceValue{}
+	for deviceId, deviceRoomId := range renderer.roomCfg.deviceMap {
+		if value, ok := renderer.deviceValues[deviceId][measurementType]; ok && deviceRoomId == roomId {
+			values = append(values, value)
+		}
+	}
+
+	maxAge, ok := renderer.maxAge[measurementType]
+	if !ok {
+		return values
+	}
+	currentValues := []deviceValue{}
+	for _, value := range values {
+		if now.Sub(value.timestamp) <= maxAge {
+			currentValues = append(currentValues, value)
+		}
+	}
+	if len(currentValues) == 0 {
+		return values
+	}
+	return currentValues
+}
+
+// weakestBattery returns battery value with lowest level.
+func (renderer *IndoorClimateRenderer) weakestBattery(values []deviceValue) deviceValue {
+
+	weakest := values[0]
+	for _, value := range values[1:] {
+		if renderer.battery.percent(value.value) < renderer.battery.percent(weakest.value) {
+			weakest = value
+		}
+	}
+	return weakest
+}
+
+// latestValue returns the most recent value of passed values.
+func latestValue(values []deviceValue) deviceValue {
+
+	latest := values[0]
+	for _, value := range values[1:] {
+		if value.timestamp.After(latest.timestamp) {
+			latest = value
+		}
+	}
+	return latest
+}
+
+// aggregateNumbers calculates average, minimum or maximum of passed values. Timestamp of the result
+// is the most recent timestamp of all values. Returns false if a value isn't a number.
+func aggregateNumbers(values []deviceValue, aggregation aggregationStrategy) (deviceValue, bool) {
+
+	numbers := []float64{}
+	for _, value := range values {
+		number, err := strconv.ParseFloat(value.value, 64)
+		if err != nil {
+			return deviceValue{}, false
+		}
+		numbers = append(numbers, number)
+	}
+
+	result := numbers[0]
+	for _, number := range numbers[1:] {
+		switch aggregation {
+		case AGGREGATION_MIN:
+			result = math.Min(result, number)
+		case AGGREGATION_MAX:
+			result = math.Max(result, number)
+		default:
+			result += number
+		}
+	}
+	if aggregation == AGGREGATION_AVERAGE {
+		result = result / float64(len(numbers))
+	}
+	return deviceValue{timestamp: latestValue(values).timestamp, value: strconv.FormatFloat(result, 'f', -1, 64)}, true
+}
@@ -0,0 +1,90 @@
+package syncsign
+
+import (
+	"github.com/stretchr/testify/suite"
+	"testing"
+	"time"
+
+	events "github.com/tommzn/hdb-events-go"
+)
+
+type AggregationTestSuite struct {
+	suite.Suite
+}
+
+func TestAggregationTestSuite(t *testing.T) {
+	suite.Run(t, new(AggregationTestSuite))
+}
+
+func (suite *AggregationTestSuite) TestAggregationStrategyFromConfig() {
+
+	roomCfg := configForRooms(loadConfigForTest(nil), "hdb.indoorclimate")
+	for _, room := range roomCfg.rooms {
+		suite.Equal(AGGREGATION_LATEST, room.aggregation)
+	}
+	suite.Equal(AGGREGATION_AVERAGE, toAggregationStrategy("Average"))
+	suite.Equal(AGGREGATION_LATEST, toAggregationStrategy("xxx"))
+}
+
+func (suite *AggregationTestSuite) TestAggregateRoomValues() {
+
+	renderer := indoorClimateRendererForTest("fixtures/testconfig10.yml")
+	suite.Equal(AGGREGATION_PRIMARY, renderer.roomCfg.rooms["4"].aggregation)
+	suite.Equal("DEVICE42", renderer.roomCfg.rooms["4"].primaryDevice)
+
+	now := time.Now()
+	temperatures := map[string]string{
+		"Device11": "20.0", "Device12": "21.0", "Device13": "22.5",
+		"Device21": "20.0", "Device22": "18.5",
+		"Device31": "20.0", "Device32": "18.5",
+		"Device41": "19.0", "Device42": "23.0",
+		"Device51": "17.0",
+	}
+	for deviceId, temperature := range temperatures {
+		renderer.addAsIndoorClimateData(indoorClimateForTest(deviceId, events.MeasurementType_TEMPERATURE, temperature, now))
+	}
+	renderer.addAsIndoorClimateData(indoorClimateForTest("Device52", events.MeasurementType_TEMPERATURE, "16.0", now.Add(-1*time.Minute)))
+	renderer.addAsIndoorClimateData(indoorClimateForTest("Device41", events.MeasurementType_BATTERY, "80", now))
+	renderer.addAsIndoorClimateData(indoorClimateForTest("Device42", events.MeasurementType_BATTERY, "30", now.Add(-1*time.Minute)))
+
+	roomClimate := renderer.sortedRoomClimateData()
+	suite.Len(roomClimate, 5)
+	suite.Equal("21.2", roomClimate[0].Temperature)
+	suite.Equal("18.5", roomClimate[1].Temperature)
+	suite.Equal("20.0", roomClimate[2].Temperature)
+	suite.Equal("23.0", roomClimate[3].Temperature)
+	suite.Equal(BATTERY_LEVEL_1_4, roomClimate[3].BatteryIcon)
+	suite.Equal("17.0", roomClimate[4].Temperature)
+}
+
+func (suite *AggregationTestSuite) TestSkipExpiredValues() {
+
+	renderer := indoorClimateRendererForTest("fixtures/testconfig10.yml")
+
+	now := time.Now()
+	renderer.addAsIndoorClimateData(indoorClimateForTest("Device42", events.MeasurementType_TEMPERATURE, "23.0", now.Add(-2*time.Hour)))
+	renderer.addAsIndoorClimateData(indoorClimateForTest("Device11", events.MeasurementType_TEMPERATURE, "10.0", now.Add(-2*time.Hour)))
+	renderer.addAsIndoorClimateData(indoorClimateForTest("Device12", events.MeasurementType_TEMPERATURE, "20.0", now))
+
+	roomClimate := renderer.sortedRoomClimateData()
+	suite.Len(roomClimate, 2)
+	suite.Equal("20.0", roomClimate[0].Temperature)
+	suite.Equal("23.0", roomClimate[1].Temperature)
+	suite.True(roomClimate[1].Stale)
+
+	renderer.addAsIndoorClimateData(indoorClimateForTest("Device41", events.MeasurementType_TEMPERATURE, "19.5", now))
+	roomClimate2 := renderer.sortedRoomClimateData()
+	suite.Equal("19.5", roomClimate2[1].Temperature)
+	suite.False(roomClimate2[1].Stale)
+}
+
+func (suite *AggregationTestSuite) TestAggregateInvalidValues() {
+
+	values := []deviceValue{
+		deviceValue{deviceId: "Device1", timestamp: time.Now(), value: "xxx"},
+		deviceValue{deviceId: "Device2", timestamp: time.Now().Add(-1 * time.Minute), value: "21.5"},
+	}
+	_, ok := aggregateNumbers(values, AGGREGATION_AVERAGE)
+	suite.False(ok)
+	suite.Equal("Device1", latestValue(values).deviceId)
+}
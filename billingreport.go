@@ -15,10 +15,15 @@ import (
 
 // NewBillingReportRenderer returns a renderer which generates items for AWS billing reports.
 func NewBillingReportRenderer(conf config.Config, logger log.Logger, template core.Template, datasource core.DataSource) *BillingReportRenderer {
+	return NewBillingReportRendererWithConfigKey(conf, "hdb.billingreport", logger, template, datasource)
+}
+
+// NewBillingReportRendererWithConfigKey returns a renderer for AWS billing reports which uses settings from passed config key, e.g. "hdb.billingreport".
+func NewBillingReportRendererWithConfigKey(conf config.Config, configKey string, logger log.Logger, template core.Template, datasource core.DataSource) *BillingReportRenderer {
 
-	anchor := anchorFromConfig(conf, "hdb.billingreport.anchor")
-	reportCurrency := conf.Get("hdb.billingreport.report_currency", config.AsStringPtr("USD"))
-	displayCurrency := conf.Get("hdb.billingreport.display_currency", config.AsStringPtr("USD"))
+	anchor := anchorFromConfig(conf, configKey+".anchor")
+	reportCurrency := conf.Get(configKey+".report_currency", config.AsStringPtr("USD"))
+	displayCurrency := conf.Get(configKey+".display_currency", config.AsStringPtr("USD"))
 	return &BillingReportRenderer{
 		template:        template,
 		anchor:          anchor,
@@ -35,12 +40,24 @@ func (renderer *BillingReportRenderer) Content() (string, error) {
 
 	renderer.logDatasource()
 
-	if renderer.billingReport == nil {
+	billingReport := renderer.latestBillingReport()
+	if billingReport == nil {
 		if err := renderer.fetchEvents(); err != nil {
 			return "", errors.New("No billing report available.")
 		}
+		billingReport = renderer.latestBillingReport()
 	}
-	return renderer.template.RenderWith(renderer.billingReport)
+	return renderer.template.RenderWith(billingReport)
+}
+
+// LatestBillingReport returns latest calculated billing report, nil if there's no billing report, yet.
+// A billing report will not be changed after calculation, it's replaced by new billing reports, only.
+func (renderer *BillingReportRenderer) latestBillingReport() *billingReportData {
+
+	renderer.lock.RLock()
+	defer renderer.lock.RUnlock()
+
+	return renderer.billingReport
 }
 
 func (renderer *BillingReportRenderer) logDatasource() {
@@ -81,10 +98,13 @@ func (renderer *BillingReportRenderer) ObserveDataSource(ctx context.Context) {
 	if renderer.reportCurrency != renderer.displayCurrency {
 		filter = append(filter, hdbcore.DATASOURCE_EXCHANGERATE)
 	}
-	renderer.dataSourceChan = renderer.datasource.Observe(&filter)
+	dataSourceChan := renderer.datasource.Observe(&filter)
+	renderer.lock.Lock()
+	renderer.dataSourceChan = dataSourceChan
+	renderer.lock.Unlock()
 	for {
 		select {
-		case message, ok := <-renderer.dataSourceChan:
+		case message, ok := <-dataSourceChan:
 			if !ok {
 				renderer.logger.Error("Error at reading datasource channel. Stop observing!")
 				return
@@ -102,6 +122,9 @@ func (renderer *BillingReportRenderer) ObserveDataSource(ctx context.Context) {
 // ProcessEvent will store latest billing report and exchange rates for comtemt remdering.
 func (renderer *BillingReportRenderer) processEvent(message proto.Message) {
 
+	renderer.lock.Lock()
+	defer renderer.lock.Unlock()
+
 	if billingReport, ok := message.(*events.BillingReport); ok {
 		renderer.logger.Debugf("Receive new billing report for %s", billingReport.BillingPeriod)
 		renderer.calculateBillingReport(billingReport)
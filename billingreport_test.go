@@ -5,6 +5,7 @@ import (
 	"github.com/stretchr/testify/suite"
 	events "github.com/tommzn/hdb-events-go"
 	"google.golang.org/protobuf/types/known/timestamppb"
+	"sync"
 	"testing"
 	"time"
 )
@@ -45,8 +46,10 @@ func (suite *BillingReportTestSuite) TestGenerateContentByObservingDataSource()
 		endChan <- true
 	}()
 	time.Sleep(1 * time.Second)
+	renderer.lock.RLock()
 	suite.NotNil(renderer.billingReport)
 	suite.True(len(renderer.exchangeRates) > 0)
+	renderer.lock.RUnlock()
 
 	content, err := renderer.Content()
 	suite.Nil(err)
@@ -73,7 +76,7 @@ func (suite *BillingReportTestSuite) TestStopDataSourceObservingOnClosedChannel(
 	}()
 	time.Sleep(100 * time.Millisecond)
 
-	close(renderer.datasource.(*datasourceMock).eventChan)
+	renderer.datasource.(*datasourceMock).closeMessageChannel()
 	select {
 	case ok := <-endChan:
 		suite.True(ok)
@@ -111,3 +114,27 @@ func (suite *BillingReportTestSuite) TestAssignExchangeRates() {
 	suite.True(ok2)
 	suite.Equal(exchangeRate1.Rate, assignedRate2.Rate)
 }
+
+func (suite *BillingReportTestSuite) TestConcurrentObserveAndRender() {
+
+	renderer := billingReportRendererForTest("fixtures/testconfig04.yml")
+	ctx, cancelFunc := context.WithCancel(context.Background())
+	defer cancelFunc()
+	go renderer.ObserveDataSource(ctx)
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				_, err := renderer.Content()
+				suite.Nil(err)
+			}
+		}()
+	}
+	for _, message := range append(billingReportForTest(), exchangeRateForTest()...) {
+		renderer.datasource.(*datasourceMock).writeToMessageChannel(message)
+	}
+	wg.Wait()
+}
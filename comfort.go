@@ -0,0 +1,35 @@
+package syncsign
+
+import (
+	config "github.com/tommzn/go-config"
+	log "github.com/tommzn/go-log"
+	core "github.com/tommzn/hdb-renderer-core"
+)
+
+// NewComfortRenderer returns a renderer which shows the number of rooms outside of their comfort range, provided by passed comfort summary.
+func NewComfortRenderer(conf config.Config, logger log.Logger, template core.Template, comfort ComfortSummary) *ComfortRenderer {
+	return NewComfortRendererWithConfigKey(conf, "hdb.comfort", logger, template, comfort)
+}
+
+// NewComfortRendererWithConfigKey returns a renderer for rooms outside of their comfort range which uses settings from passed config key, e.g. "hdb.comfort".
+func NewComfortRendererWithConfigKey(conf config.Config, configKey string, logger log.Logger, template core.Template, comfort ComfortSummary) *ComfortRenderer {
+	return &ComfortRenderer{
+		template: template,
+		anchor:   anchorFromConfig(conf, configKey+".anchor"),
+		logger:   logger,
+		comfort:  comfort,
+	}
+}
+
+// Content generates an alert with the number of rooms outside of their comfort range.
+// Nothing is generated if all rooms are within their comfort range.
+func (renderer *ComfortRenderer) Content() (string, error) {
+
+	defer renderer.logger.Flush()
+
+	roomsOutOfRange := renderer.comfort.RoomsOutOfRange()
+	if roomsOutOfRange == 0 {
+		return "", nil
+	}
+	return renderer.template.RenderWith(comfortData{Anchor: renderer.anchor, RoomsOutOfRange: roomsOutOfRange})
+}
@@ -0,0 +1,38 @@
+package syncsign
+
+import (
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+type ComfortTestSuite struct {
+	suite.Suite
+}
+
+func TestComfortTestSuite(t *testing.T) {
+	suite.Run(t, new(ComfortTestSuite))
+}
+
+func (suite *ComfortTestSuite) TestGenerateContent() {
+
+	renderer := comfortRendererForTest(indoorClimateRendererForTest("fixtures/testconfig09.yml"))
+
+	items, err := itemsFromRenderer(renderer)
+	suite.Nil(err)
+	suite.Len(items, 1)
+	textData := items[0].Data.(*TextData)
+	suite.Equal("hdb.comfort", textData.Id)
+	suite.Equal("1 room out of comfort range", textData.Text)
+	suite.Equal(Block{X: 500, Y: 450, W: 300, H: 20}, textData.Block)
+
+	items2, err := itemsFromRenderer(comfortRendererForTest(newComfortSummaryMock(3)))
+	suite.Nil(err)
+	suite.Equal("3 rooms out of comfort range", items2[0].Data.(*TextData).Text)
+}
+
+func (suite *ComfortTestSuite) TestAllRoomsInComfortRange() {
+
+	content, err := comfortRendererForTest(newComfortSummaryMock(0)).Content()
+	suite.Nil(err)
+	suite.Equal("", content)
+}
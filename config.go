@@ -1,16 +1,25 @@
 package syncsign
 
-import config "github.com/tommzn/go-config"
+import (
+	"strings"
+
+	config "github.com/tommzn/go-config"
+)
 
 // NewDisplayConfig extracts list of display ids from passed config and returns a
 // DisplayCondig which can be used to ensure valid display ids.
+// Each display can define a comma separated list of widgets, which defaults to all available widgets.
 func NewDisplayConfig(conf config.Config) *DisplayConfig {
 
-	displays := make(map[string]struct{})
+	displays := make(map[string]display)
 	displaysCfg := conf.GetAsSliceOfMaps("hdb.displays")
 	for _, displayCfg := range displaysCfg {
 		if displayId, ok := displayCfg["id"]; ok {
-			displays[displayId] = struct{}{}
+			widgets := defaultWidgets()
+			if widgetList, ok := displayCfg["widgets"]; ok {
+				widgets = widgetsFromList(widgetList)
+			}
+			displays[displayId] = display{Id: displayId, Widgets: widgets}
 		}
 	}
 	return &DisplayConfig{displays: displays}
@@ -30,3 +39,60 @@ func (cfg *DisplayConfig) All() []string {
 	}
 	return displayIds
 }
+
+// Widgets returns names of all widgets which should be rendered for passed display.
+// Each widget name refers to a config section, e.g. "indoorclimate" for "hdb.indoorclimate".
+// An empty list is returned for unknown displays.
+func (cfg *DisplayConfig) Widgets(displayId string) []string {
+	if display, ok := cfg.displays[displayId]; ok {
+		return display.Widgets
+	}
+	return []string{}
+}
+
+// WidgetType returns the type of passed widget, defined by "hdb.<widget>.type".
+// If there's no type config, widget name is used as type.
+func WidgetType(conf config.Config, widget string) string {
+	widgetType := conf.Get("hdb."+widget+".type", config.AsStringPtr(widget))
+	return strings.ToLower(*widgetType)
+}
+
+// RoomTemplateFiles returns template files of all rooms of an indoor climate widget which define their own
+// template, by room id. Files are relative to template directory.
+func RoomTemplateFiles(conf config.Config, configKey string) map[string]string {
+
+	templateFiles := make(map[string]string)
+	for roomId, room := range configForRooms(conf, configKey).rooms {
+		if room.template != "" {
+			templateFiles[roomId] = room.template
+		}
+	}
+	return templateFiles
+}
+
+// GroupHeaderTemplateFile returns the group header template file of an indoor climate widget, relative to template
+// directory. If rooms are grouped and there's no template defined by "group_header.template", default header
+// template is returned. Empty string is returned if there're no groups.
+func GroupHeaderTemplateFile(conf config.Config, configKey string) string {
+
+	if len(configForRooms(conf, configKey).groups) == 0 {
+		return ""
+	}
+	return *conf.Get(configKey+".group_header.template", config.AsStringPtr(DEFAULT_GROUP_HEADER_TEMPLATE))
+}
+
+// defaultWidgets returns the list of widgets used for a display without widget config.
+func defaultWidgets() []string {
+	return []string{WIDGET_INDOORCLIMATE, WIDGET_BILLINGREPORT, WIDGET_WEATHER, WIDGET_TIMESTAMP}
+}
+
+// widgetsFromList splits a comma separated list of widget names.
+func widgetsFromList(widgetList string) []string {
+	widgets := []string{}
+	for _, widget := range strings.Split(widgetList, ",") {
+		if widget = strings.TrimSpace(widget); widget != "" {
+			widgets = append(widgets, widget)
+		}
+	}
+	return widgets
+}
@@ -24,3 +24,30 @@ func (suite *ConfigTestSuite) TestDisplayCondig() {
 
 	suite.Len(displayConfig.All(), 2)
 }
+
+func (suite *ConfigTestSuite) TestDisplayWidgets() {
+
+	conf := loadConfigForTest(config.AsStringPtr("fixtures/testconfig07.yml"))
+	displayConfig := NewDisplayConfig(conf)
+
+	suite.Equal([]string{"hallway_weather", "timestamp"}, displayConfig.Widgets("Hallway"))
+	suite.Equal([]string{"billingreport", "indoorclimate", "timestamp"}, displayConfig.Widgets("Office"))
+	suite.Equal(defaultWidgets(), displayConfig.Widgets("Kitchen"))
+	suite.Len(displayConfig.Widgets("Bedroom"), 0)
+
+	suite.Equal(WIDGET_WEATHER, WidgetType(conf, "hallway_weather"))
+	suite.Equal(WIDGET_TIMESTAMP, WidgetType(conf, "timestamp"))
+}
+
+func (suite *ConfigTestSuite) TestRoomTemplateFiles() {
+
+	conf := loadConfigForTest(config.AsStringPtr("fixtures/testconfig16.yml"))
+	suite.Equal(map[string]string{"1": "indoorclimate_tile.json", "3": "xxx.json"}, RoomTemplateFiles(conf, "hdb.indoorclimate"))
+	suite.Len(RoomTemplateFiles(conf, "hdb.xxx"), 0)
+}
+
+func (suite *ConfigTestSuite) TestGroupHeaderTemplateFile() {
+
+	suite.Equal(DEFAULT_GROUP_HEADER_TEMPLATE, GroupHeaderTemplateFile(loadConfigForTest(config.AsStringPtr("fixtures/testconfig17.yml")), "hdb.indoorclimate"))
+	suite.Equal("", GroupHeaderTemplateFile(loadConfigForTest(config.AsStringPtr("fixtures/testconfig16.yml")), "hdb.indoorclimate"))
+}
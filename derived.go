@@ -0,0 +1,55 @@
+package syncsign
+
+import (
+	"fmt"
+	"math"
+)
+
+// applyDerivedValues calculates dew point, absolute humidity and heat index from current temperature
+// and humidity of passed room climate. Dew point and heat index are converted into used temperature unit.
+func (renderer *IndoorClimateRenderer) applyDerivedValues(roomClimate *indoorCliemate) {
+
+	if roomClimate.temperature == nil || roomClimate.humidity == nil || *roomClimate.humidity <= 0 {
+		return
+	}
+	temperature, humidity := *roomClimate.temperature, *roomClimate.humidity
+	roomClimate.DewPoint = formatWithPrecision(toTemperatureUnit(dewPoint(temperature, humidity), renderer.unit), renderer.precision.dewPoint)
+	roomClimate.AbsoluteHumidity = formatWithPrecision(absoluteHumidity(temperature, humidity), renderer.precision.absoluteHumidity)
+	roomClimate.HeatIndex = formatWithPrecision(toTemperatureUnit(heatIndex(temperature, humidity), renderer.unit), renderer.precision.heatIndex)
+}
+
+// dewPoint calculates dew point in °C from temperature in °C and relative humidity in percent, using Magnus formula.
+func dewPoint(temperature, humidity float64) float64 {
+	gamma := math.Log(humidity/100) + 17.62*temperature/(243.12+temperature)
+	return 243.12 * gamma / (17.62 - gamma)
+}
+
+// absoluteHumidity calculates absolute humidity in g/m³ from temperature in °C and relative humidity in percent.
+func absoluteHumidity(temperature, humidity float64) float64 {
+	saturationVaporPressure := 6.112 * math.Exp(17.67*temperature/(temperature+243.5))
+	return saturationVaporPressure * humidity * 2.1674 / (273.15 + temperature)
+}
+
+// heatIndex calculates perceived temperature in °C from temperature in °C and relative humidity in percent,
+// using the heat index equation of the US National Weather Service.
+func heatIndex(temperature, humidity float64) float64 {
+
+	fahrenheit := temperature*9/5 + 32
+	index := 0.5 * (fahrenheit + 61 + (fahrenheit-68)*1.2 + humidity*0.094)
+	if (index+fahrenheit)/2 >= 80 {
+		index = -42.379 + 2.04901523*fahrenheit + 10.14333127*humidity - 0.22475541*fahrenheit*humidity -
+			0.00683783*fahrenheit*fahrenheit - 0.05481717*humidity*humidity + 0.00122874*fahrenheit*fahrenheit*humidity +
+			0.00085282*fahrenheit*humidity*humidity - 0.00000199*fahrenheit*fahrenheit*humidity*humidity
+		if humidity < 13 && fahrenheit >= 80 && fahrenheit <= 112 {
+			index -= (13 - humidity) / 4 * math.Sqrt((17-math.Abs(fahrenheit-95))/17)
+		} else if humidity > 85 && fahrenheit >= 80 && fahrenheit <= 87 {
+			index += (humidity - 85) / 10 * (87 - fahrenheit) / 5
+		}
+	}
+	return (index - 32) * 5 / 9
+}
+
+// formatWithPrecision formats passed value with given number of decimals.
+func formatWithPrecision(value float64, precision int) string {
+	return fmt.Sprintf("%.*f", precision, value)
+}
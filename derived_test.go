@@ -0,0 +1,49 @@
+package syncsign
+
+import (
+	"github.com/stretchr/testify/suite"
+	"testing"
+	"time"
+
+	events "github.com/tommzn/hdb-events-go"
+)
+
+type DerivedTestSuite struct {
+	suite.Suite
+}
+
+func TestDerivedTestSuite(t *testing.T) {
+	suite.Run(t, new(DerivedTestSuite))
+}
+
+func (suite *DerivedTestSuite) TestCalculateDerivedValues() {
+
+	suite.Equal("9.26", formatWithPrecision(dewPoint(20, 50), 2))
+	suite.Equal("8.64", formatWithPrecision(absoluteHumidity(20, 50), 2))
+	suite.Equal("19.36", formatWithPrecision(heatIndex(20, 50), 2))
+
+	suite.Equal("25.84", formatWithPrecision(dewPoint(32, 70), 2))
+	suite.Equal("23.66", formatWithPrecision(absoluteHumidity(32, 70), 2))
+	suite.Equal("40.41", formatWithPrecision(heatIndex(32, 70), 2))
+
+	suite.Equal("34.00", formatWithPrecision(heatIndex(28, 90), 2))
+	suite.Equal("31.92", formatWithPrecision(heatIndex(35, 10), 2))
+}
+
+func (suite *DerivedTestSuite) TestDerivedRoomClimate() {
+
+	renderer := indoorClimateRendererForTest("fixtures/testconfig12.yml")
+	renderer.initIndoorClimateData()
+
+	roomClimate := renderer.sortedRoomClimateData()
+	suite.Len(roomClimate, 1)
+	suite.Equal("10.46", roomClimate[0].DewPoint)
+	suite.Equal("9", roomClimate[0].AbsoluteHumidity)
+	suite.Equal("16.6", roomClimate[0].HeatIndex)
+
+	renderer.addAsIndoorClimateData(indoorClimateForTest("Device3", events.MeasurementType_TEMPERATURE, "21", time.Now()))
+	roomClimate2 := renderer.sortedRoomClimateData()
+	suite.Len(roomClimate2, 2)
+	suite.Equal("Room3", roomClimate2[0].RoomName)
+	suite.Equal("--", roomClimate2[0].DewPoint)
+}
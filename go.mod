@@ -16,8 +16,9 @@ require (
 	github.com/tommzn/go-utils v1.0.2
 	github.com/tommzn/hdb-core v1.0.3
 	github.com/tommzn/hdb-events-go v1.0.13
-	github.com/tommzn/hdb-message-client v1.1.12 // indirect
+	github.com/tommzn/hdb-message-client v1.1.12
 	github.com/tommzn/hdb-renderer-core v1.1.6
+	golang.org/x/image v0.0.0-20220302094943-723b81ca9867
 	golang.org/x/sys v0.0.0-20220224120231-95c6836cb0e7 // indirect
 	google.golang.org/protobuf v1.27.1
 	gopkg.in/ini.v1 v1.66.4 // indirect
@@ -2,8 +2,11 @@ package syncsign
 
 import (
 	"context"
+	"errors"
 	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/golang/protobuf/proto"
 	config "github.com/tommzn/go-config"
@@ -15,26 +18,68 @@ import (
 
 // NewIndoorClimateRenderer returns a new renderer for infoor climate data. Room will be taken from passed config, template and datasource have to be passed.
 func NewIndoorClimateRenderer(conf config.Config, logger log.Logger, template core.Template, datasource core.DataSource) *IndoorClimateRenderer {
+	return NewIndoorClimateRendererWithConfigKey(conf, "hdb.indoorclimate", logger, template, datasource)
+}
+
+// NewIndoorClimateRendererWithConfigKey returns a new renderer for indoor climate data which uses settings from passed config key,
+// e.g. "hdb.indoorclimate". Can be used to run multiple indoor climate widgets with different settings.
+func NewIndoorClimateRendererWithConfigKey(conf config.Config, configKey string, logger log.Logger, template core.Template, datasource core.DataSource) *IndoorClimateRenderer {
+	return NewIndoorClimateRendererWithTemplates(conf, configKey, logger, IndoorClimateTemplates{Room: template}, datasource)
+}
 
-	anchor := anchorFromConfig(conf, "hdb.indoorclimate.anchor")
-	size := sizeFromConfig(conf, "hdb.indoorclimate.size")
-	spacing := spacingFromConfig(conf, "hdb.indoorclimate.spacing")
-	roomCfg := configForRooms(conf, "hdb.indoorclimate")
-	return &IndoorClimateRenderer{
-		originAnchor: anchor,
-		size:         size,
-		spacing:      spacing,
-		datasource:   datasource,
-		template:     template,
-		logger:       logger,
-		roomClimate:  make(map[string]indoorCliemate),
-		roomCfg:      roomCfg,
-		timestapMgr:  core.NewTimestampManager(),
+// NewIndoorClimateRendererWithTemplates returns a new renderer for indoor climate data which uses settings from passed config key
+// and given templates, e.g. if rooms should use their own template. See RoomTemplateFiles for templates defined by room config.
+func NewIndoorClimateRendererWithTemplates(conf config.Config, configKey string, logger log.Logger, templates IndoorClimateTemplates, datasource core.DataSource) *IndoorClimateRenderer {
+
+	anchor := anchorFromConfig(conf, configKey+".anchor")
+	size := sizeFromConfig(conf, configKey+".size")
+	spacing := spacingFromConfig(conf, configKey+".spacing")
+	roomCfg := configForRooms(conf, configKey)
+	location := locationFromConfig(conf, configKey+".timezone", logger)
+	renderer := &IndoorClimateRenderer{
+		originAnchor:   anchor,
+		size:           size,
+		spacing:        spacing,
+		layout:         gridLayoutFromConfig(conf, configKey+".layout"),
+		datasource:     datasource,
+		template:       templates.Room,
+		logger:         logger,
+		roomClimate:    make(map[string]indoorCliemate),
+		roomCfg:        roomCfg,
+		timestapMgr:    core.NewTimestampManager(),
+		maxAge:         maxAgeFromConfig(conf, configKey+".max_age"),
+		staleColor:     textColorFromConfig(conf, configKey+".stale_color", COLOR_RED),
+		lastUpdates:    make(map[string]map[events.MeasurementType]time.Time),
+		location:       location,
+		dailyExtremes:  make(map[string]map[events.MeasurementType]dailyExtreme),
+		trend:          trendConfigFromConfig(conf, configKey+".trend"),
+		history:        make(map[string]map[events.MeasurementType][]reading),
+		battery:        batteryConfigFromConfig(conf, configKey+".battery"),
+		unmapped:       make(map[string]UnmappedDevice),
+		deviceValues:   make(map[string]map[events.MeasurementType]deviceValue),
+		allRooms:       *conf.GetAsBool(configKey+".all_rooms", config.AsBoolPtr(false)),
+		order:          toRoomOrder(*conf.Get(configKey+".order", config.AsStringPtr(""))),
+		precision:      precisionFromConfig(conf, configKey+".precision"),
+		unit:           temperatureUnitFromConfig(conf, configKey+".unit"),
+		mold:           moldConfigFromConfig(conf, configKey+".mold"),
+		moldHistory:    make(map[string]moldHistory),
+		roomTemplates:  make(map[string]core.Template),
+		headerTemplate: templates.GroupHeader,
+		headerHeight:   forcePositive(*conf.GetAsInt(configKey+".group_header.height", config.AsIntPtr(30))),
+		offlineAfter:   *conf.GetAsDuration(configKey+".offline_after", config.AsDurationPtr(0)),
+		deviceLastSeen: make(map[string]time.Time),
+		startTime:      time.Now(),
+		slots:          slotsFromConfig(conf, configKey+".slots", logger),
+	}
+	for roomId, template := range templates.Rooms {
+		renderer.roomTemplates[roomId] = template
 	}
+	return renderer
 }
 
 // Content fetches current inddor climate data and generated room climate elements based
-// pn given room/device config.
+// pn given room/device config. Rooms with their own anchor are placed at it, all other rooms
+// are arranged by used layout.
 func (renderer *IndoorClimateRenderer) Content() (string, error) {
 
 	defer renderer.logger.Flush()
@@ -46,40 +91,133 @@ func (renderer *IndoorClimateRenderer) Content() (string, error) {
 
 	// Init indoor climate data from used datasource if nothing is available
 	// or if renderer doesn't observer datasource actively.
-	if len(renderer.roomClimate) == 0 || renderer.dataSourceChan == nil {
+	if renderer.needsInit() {
 		renderer.initIndoorClimateData()
 	}
 
-	if len(renderer.roomClimate) == 0 {
+	roomClimate := renderer.sortedRoomClimateData()
+	if len(roomClimate) == 0 {
 		renderer.logger.Error("No room climate to render.")
 		return "", nil
 	}
 
 	content := ""
-	anchor := renderer.originAnchor
-	roomClimate := renderer.sortedRoomClimateData()
+	autoPlaced := []indoorCliemate{}
 	for _, climate := range roomClimate {
-		climate.Anchor = anchor
-		elementContent, err := renderer.template.RenderWith(climate)
+		anchor := renderer.roomCfg.rooms[climate.roomId].anchor
+		if anchor == nil {
+			autoPlaced = append(autoPlaced, climate)
+			continue
+		}
+		climate.Anchor = *anchor
+		elementContent, err := renderer.templateFor(climate.roomId).RenderWith(climate)
 		if err != nil {
 			return "", err
 		}
 		content = content + elementContent
-		anchor.X = anchor.X + renderer.size.Width + renderer.spacing.Left + renderer.spacing.Right
+	}
+
+	origin := renderer.originAnchor
+	for _, section := range renderer.sectionsOf(autoPlaced) {
+		if section.header != nil {
+			if renderer.headerTemplate == nil {
+				return "", errors.New("No template for group headers.")
+			}
+			section.header.Anchor = origin
+			headerContent, err := renderer.headerTemplate.RenderWith(section.header)
+			if err != nil {
+				return "", err
+			}
+			content = content + headerContent
+			origin.Y += renderer.headerHeight
+		}
+		bottom := origin.Y
+		for idx, climate := range section.rooms {
+			climate.Anchor = renderer.anchorForElement(origin, idx, len(section.rooms))
+			elementContent, err := renderer.templateFor(climate.roomId).RenderWith(climate)
+			if err != nil {
+				return "", err
+			}
+			content = content + elementContent
+			if elementBottom := climate.Anchor.Y + renderer.size.Height + renderer.spacing.Top + renderer.spacing.Bottom; elementBottom > bottom {
+				bottom = elementBottom
+			}
+		}
+		origin.Y = bottom
 	}
 	return content, nil
 }
 
+// sectionsOf splits passed rooms into sections of their groups, in order of groups defined by config.
+// Each group gets a header. Rooms without a group, or with an unknown group, are added as last section without header.
+func (renderer *IndoorClimateRenderer) sectionsOf(roomClimate []indoorCliemate) []roomSection {
+
+	groupedRooms := make(map[string][]indoorCliemate)
+	ungrouped := []indoorCliemate{}
+	for _, climate := range roomClimate {
+		groupId := renderer.roomCfg.rooms[climate.roomId].group
+		if renderer.roomCfg.hasGroup(groupId) {
+			groupedRooms[groupId] = append(groupedRooms[groupId], climate)
+		} else {
+			ungrouped = append(ungrouped, climate)
+		}
+	}
+
+	sections := []roomSection{}
+	for idx, group := range renderer.roomCfg.groups {
+		if rooms, ok := groupedRooms[group.id]; ok {
+			header := groupHeader{GroupId: group.id, Name: group.name, DisplayIndex: idx}
+			sections = append(sections, roomSection{header: &header, rooms: rooms})
+		}
+	}
+	if len(ungrouped) > 0 {
+		sections = append(sections, roomSection{rooms: ungrouped})
+	}
+	return sections
+}
+
+// templateFor returns the template of passed room, default template is used if a room doesn't have it's own template.
+func (renderer *IndoorClimateRenderer) templateFor(roomId string) core.Template {
+	if template, ok := renderer.roomTemplates[roomId]; ok {
+		return template
+	}
+	return renderer.template
+}
+
+// anchorForElement returns the anchor of a room element at passed position, relative to given origin and depending on
+// used grid layout. Without columns all elements are placed in a single row, or a single column for vertical direction.
+func (renderer *IndoorClimateRenderer) anchorForElement(origin core.Point, idx, count int) core.Point {
+
+	column, row := idx, 0
+	if renderer.layout.direction == LAYOUT_VERTICAL {
+		column, row = 0, idx
+		if renderer.layout.columns > 0 {
+			rows := (count + renderer.layout.columns - 1) / renderer.layout.columns
+			column, row = idx/rows, idx%rows
+		}
+	} else if renderer.layout.columns > 0 {
+		column, row = idx%renderer.layout.columns, idx/renderer.layout.columns
+	}
+
+	return core.Point{
+		X: origin.X + column*(renderer.size.Width+renderer.spacing.Left+renderer.spacing.Right),
+		Y: origin.Y + row*(renderer.size.Height+renderer.spacing.Top+renderer.spacing.Bottom),
+	}
+}
+
 // ObserveDataSource will listen for new indoor climate data provided by used datasource.
 func (renderer *IndoorClimateRenderer) ObserveDataSource(ctx context.Context) {
 
 	defer renderer.logger.Flush()
 
 	filter := []hdbcore.DataSource{hdbcore.DATASOURCE_INDOORCLIMATE}
-	renderer.dataSourceChan = renderer.datasource.Observe(&filter)
+	dataSourceChan := renderer.datasource.Observe(&filter)
+	renderer.lock.Lock()
+	renderer.dataSourceChan = dataSourceChan
+	renderer.lock.Unlock()
 	for {
 		select {
-		case message, ok := <-renderer.dataSourceChan:
+		case message, ok := <-dataSourceChan:
 			if !ok {
 				renderer.logger.Error("Error at reading datasource channel. Stop observing!")
 				return
@@ -94,12 +232,31 @@ func (renderer *IndoorClimateRenderer) ObserveDataSource(ctx context.Context) {
 	}
 }
 
+// needsInit returns true if there's no indoor climate data or if datasource isn't observed.
+func (renderer *IndoorClimateRenderer) needsInit() bool {
+
+	renderer.lock.RLock()
+	defer renderer.lock.RUnlock()
+
+	return len(renderer.roomClimate) == 0 || renderer.dataSourceChan == nil
+}
+
 // InitIndoorClimateData will dop existing indoor climate data and fetch all available events from used datasource.
 func (renderer *IndoorClimateRenderer) initIndoorClimateData() {
 
-	renderer.roomClimate = make(map[string]indoorCliemate)
-
 	messages, err := renderer.datasource.All(hdbcore.DATASOURCE_INDOORCLIMATE)
+
+	renderer.lock.Lock()
+	defer renderer.lock.Unlock()
+
+	renderer.roomClimate = make(map[string]indoorCliemate)
+	renderer.timestapMgr = core.NewTimestampManager()
+	renderer.lastUpdates = make(map[string]map[events.MeasurementType]time.Time)
+	renderer.dailyExtremes = make(map[string]map[events.MeasurementType]dailyExtreme)
+	renderer.history = make(map[string]map[events.MeasurementType][]reading)
+	renderer.deviceValues = make(map[string]map[events.MeasurementType]deviceValue)
+	renderer.moldHistory = make(map[string]moldHistory)
+	renderer.deviceLastSeen = make(map[string]time.Time)
 	if err != nil {
 		renderer.logger.Error("Unable to get indoor climate, reason: ", err)
 		return
@@ -107,13 +264,23 @@ func (renderer *IndoorClimateRenderer) initIndoorClimateData() {
 	renderer.logger.Infof("Fetch %d indoor climate messages", len(messages))
 
 	for _, message := range messages {
-		renderer.addAsIndoorClimateData(message)
+		renderer.assignIndoorClimateData(message)
 	}
 }
 
 // addAsIndoorClimateData will try to add passed message to local indoor climate data.
 func (renderer *IndoorClimateRenderer) addAsIndoorClimateData(message proto.Message) {
 
+	renderer.lock.Lock()
+	defer renderer.lock.Unlock()
+
+	renderer.assignIndoorClimateData(message)
+}
+
+// assignIndoorClimateData assigns passed message to climate data of the room it's device belongs to.
+// Caller has to hold the lock.
+func (renderer *IndoorClimateRenderer) assignIndoorClimateData(message proto.Message) {
+
 	indoorClimate, ok := message.(*events.IndoorClimate)
 	if !ok {
 		return
@@ -122,26 +289,367 @@ func (renderer *IndoorClimateRenderer) addAsIndoorClimateData(message proto.Mess
 	deviceId := strings.ToUpper(indoorClimate.DeviceId)
 	roomId, ok := renderer.roomCfg.deviceMap[deviceId]
 	if !ok {
+		renderer.addUnmappedDevice(indoorClimate)
 		return
 	}
 
-	if !renderer.timestapMgr.IsLatestWithSuffix(message, roomId) {
+	if lastSeen := indoorClimate.Timestamp.AsTime(); lastSeen.After(renderer.deviceLastSeen[deviceId]) {
+		renderer.deviceLastSeen[deviceId] = lastSeen
+	}
+	if !renderer.timestapMgr.IsLatestWithSuffix(message, deviceId) {
 		return
 	}
+	renderer.timestapMgr.AddWithSuffix(message, deviceId)
+	renderer.addDeviceValue(indoorClimate.Type, deviceValue{
+		deviceId:  deviceId,
+		timestamp: indoorClimate.Timestamp.AsTime(),
+		value:     renderer.roomCfg.calibrate(deviceId, indoorClimate.Type, indoorClimate.Value),
+	})
 
 	renderer.logger.Debugf("Receive new indoor climate data, %s, %s", indoorClimate.Type, indoorClimate.Value)
+	roomValue := renderer.aggregatedValue(roomId, indoorClimate.Type, indoorClimate.Timestamp.AsTime())
 	roomClimate := renderer.getRoomClimate(roomId)
 	switch indoorClimate.Type {
 	case events.MeasurementType_TEMPERATURE:
-		roomClimate.Temperature = formatTemperature(indoorClimate.Value)
+		roomClimate.Temperature = formatTemperature(roomValue.value, renderer.unit, renderer.precision.temperature)
+		roomClimate.temperature = parseValue(roomValue.value)
 	case events.MeasurementType_HUMIDITY:
-		roomClimate.Humidity = formatHumidity(indoorClimate.Value)
+		roomClimate.Humidity = formatHumidity(roomValue.value, renderer.precision.humidity)
+		roomClimate.humidity = parseValue(roomValue.value)
 	case events.MeasurementType_BATTERY:
-		roomClimate.BatteryIcon = batteryIcon(indoorClimate.Value)
-		roomClimate.BatteryIconColor = batteryIconColor(indoorClimate.Value)
+		batteryLevel := renderer.battery.levelFor(roomValue.value)
+		roomClimate.BatteryIcon = batteryLevel.icon
+		roomClimate.BatteryIconColor = batteryLevel.color
 	}
+	roomClimate.Slots = renderer.withSlotValue(roomClimate.Slots, indoorClimate.Type, roomValue.value)
 	renderer.roomClimate[roomId] = roomClimate
-	renderer.timestapMgr.AddWithSuffix(message, roomId)
+	renderer.addLastUpdate(roomId, indoorClimate.Type, roomValue.timestamp)
+	if value, err := strconv.ParseFloat(roomValue.value, 64); err == nil {
+		measurement := reading{timestamp: indoorClimate.Timestamp.AsTime(), value: value}
+		renderer.addToDailyExtremes(roomId, indoorClimate.Type, measurement)
+		renderer.addToHistory(roomId, indoorClimate.Type, measurement)
+		if indoorClimate.Type == events.MeasurementType_HUMIDITY {
+			renderer.addToMoldHistory(roomId, measurement, roomClimate.temperature)
+		}
+	}
+}
+
+// addToDailyExtremes updates minimum and maximum of passed measurement for the day it has been taken.
+// Extremes of a previous day are dropped. Caller has to hold the lock.
+func (renderer *IndoorClimateRenderer) addToDailyExtremes(roomId string, measurementType events.MeasurementType, measurement reading) {
+
+	day := renderer.dayOf(measurement.timestamp)
+	if _, ok := renderer.dailyExtremes[roomId]; !ok {
+		renderer.dailyExtremes[roomId] = make(map[events.MeasurementType]dailyExtreme)
+	}
+	extreme, ok := renderer.dailyExtremes[roomId][measurementType]
+	if !ok || extreme.day != day {
+		renderer.dailyExtremes[roomId][measurementType] = dailyExtreme{day: day, min: measurement.value, max: measurement.value}
+		return
+	}
+	if measurement.value < extreme.min {
+		extreme.min = measurement.value
+	}
+	if measurement.value > extreme.max {
+		extreme.max = measurement.value
+	}
+	renderer.dailyExtremes[roomId][measurementType] = extreme
+}
+
+// addToHistory appends passed measurement to the history of given room and drops all
+// measurements which are outside of the trend window. Caller has to hold the lock.
+func (renderer *IndoorClimateRenderer) addToHistory(roomId string, measurementType events.MeasurementType, measurement reading) {
+
+	if _, ok := renderer.history[roomId]; !ok {
+		renderer.history[roomId] = make(map[events.MeasurementType][]reading)
+	}
+	windowStart := measurement.timestamp.Add(-1 * renderer.trend.window)
+	history := []reading{}
+	for _, previous := range renderer.history[roomId][measurementType] {
+		if !previous.timestamp.Before(windowStart) {
+			history = append(history, previous)
+		}
+	}
+	renderer.history[roomId][measurementType] = append(history, measurement)
+}
+
+// applyTrends assigns trends of temperature and humidity to passed room climate. Caller has to hold the lock.
+func (renderer *IndoorClimateRenderer) applyTrends(roomId string, roomClimate *indoorCliemate, now time.Time) {
+
+	roomClimate.TemperatureTrend, roomClimate.TemperatureTrendIcon = renderer.trendOf(roomId, events.MeasurementType_TEMPERATURE, now)
+	roomClimate.HumidityTrend, roomClimate.HumidityTrendIcon = renderer.trendOf(roomId, events.MeasurementType_HUMIDITY, now)
+}
+
+// trendOf compares latest and oldest value of a measurement type within trend window.
+// A trend is steady if the change of a value doesn't exceed the threshold of it's measurement type.
+// Caller has to hold the lock.
+func (renderer *IndoorClimateRenderer) trendOf(roomId string, measurementType events.MeasurementType, now time.Time) (trendDirection, trendIcon) {
+
+	windowStart := now.Add(-1 * renderer.trend.window)
+	var oldest, latest *reading
+	for idx, measurement := range renderer.history[roomId][measurementType] {
+		if measurement.timestamp.Before(windowStart) {
+			continue
+		}
+		if oldest == nil {
+			oldest = &renderer.history[roomId][measurementType][idx]
+		}
+		latest = &renderer.history[roomId][measurementType][idx]
+	}
+	if oldest == nil {
+		return TREND_STEADY, TREND_ICON_STEADY
+	}
+
+	change := latest.value - oldest.value
+	threshold := renderer.trend.thresholds[measurementType]
+	switch {
+	case change > threshold:
+		return TREND_RISING, TREND_ICON_RISING
+	case change < -1*threshold:
+		return TREND_FALLING, TREND_ICON_FALLING
+	default:
+		return TREND_STEADY, TREND_ICON_STEADY
+	}
+}
+
+// applyDailyExtremes assigns today's extremes of temperature and humidity to passed room climate.
+// Caller has to hold the lock.
+func (renderer *IndoorClimateRenderer) applyDailyExtremes(roomId string, roomClimate *indoorCliemate, now time.Time) {
+
+	today := renderer.dayOf(now)
+	for measurementType, extreme := range renderer.dailyExtremes[roomId] {
+		if extreme.day != today {
+			continue
+		}
+		switch measurementType {
+		case events.MeasurementType_TEMPERATURE:
+			roomClimate.TemperatureMin = formatTemperature(strconv.FormatFloat(extreme.min, 'f', -1, 64), renderer.unit, renderer.precision.temperature)
+			roomClimate.TemperatureMax = formatTemperature(strconv.FormatFloat(extreme.max, 'f', -1, 64), renderer.unit, renderer.precision.temperature)
+		case events.MeasurementType_HUMIDITY:
+			roomClimate.HumidityMin = formatHumidity(strconv.FormatFloat(extreme.min, 'f', -1, 64), renderer.precision.humidity)
+			roomClimate.HumidityMax = formatHumidity(strconv.FormatFloat(extreme.max, 'f', -1, 64), renderer.precision.humidity)
+		}
+	}
+}
+
+// dayOf returns the day of passed time in used timezone, e.g. "2022-03-14".
+func (renderer *IndoorClimateRenderer) dayOf(t time.Time) string {
+	return t.In(renderer.location).Format("2006-01-02")
+}
+
+// addUnmappedDevice keeps last seen time and latest values of a device which isn't assigned to a room.
+// Each unmapped device is logged once. Caller has to hold the lock.
+func (renderer *IndoorClimateRenderer) addUnmappedDevice(indoorClimate *events.IndoorClimate) {
+
+	device, ok := renderer.unmapped[indoorClimate.DeviceId]
+	if !ok {
+		renderer.logger.Infof("Receive indoor climate data for unmapped device: %s", indoorClimate.DeviceId)
+		device = UnmappedDevice{DeviceId: indoorClimate.DeviceId, Values: make(map[string]string)}
+	}
+
+	timestamp := indoorClimate.Timestamp.AsTime()
+	if timestamp.Before(device.LastSeen) {
+		return
+	}
+	device.LastSeen = timestamp
+	device.Values[strings.ToLower(indoorClimate.Type.String())] = indoorClimate.Value
+	renderer.unmapped[indoorClimate.DeviceId] = device
+}
+
+// UnmappedDevices returns all devices which send indoor climate data but aren't assigned to a room, sorted by device id.
+func (renderer *IndoorClimateRenderer) UnmappedDevices() []UnmappedDevice {
+
+	renderer.lock.RLock()
+	defer renderer.lock.RUnlock()
+
+	devices := []UnmappedDevice{}
+	for _, device := range renderer.unmapped {
+		values := make(map[string]string)
+		for measurementType, value := range device.Values {
+			values[measurementType] = value
+		}
+		devices = append(devices, UnmappedDevice{DeviceId: device.DeviceId, LastSeen: device.LastSeen, Values: values})
+	}
+	sort.Slice(devices, func(i, j int) bool {
+		return devices[i].DeviceId < devices[j].DeviceId
+	})
+	return devices
+}
+
+// addLastUpdate keeps passed time as last update of a measurement type for given room.
+// Caller has to hold the lock.
+func (renderer *IndoorClimateRenderer) addLastUpdate(roomId string, measurementType events.MeasurementType, lastUpdate time.Time) {
+
+	if _, ok := renderer.lastUpdates[roomId]; !ok {
+		renderer.lastUpdates[roomId] = make(map[events.MeasurementType]time.Time)
+	}
+	renderer.lastUpdates[roomId][measurementType] = lastUpdate
+}
+
+// applyComfortRanges flags temperature and humidity of passed room climate which are outside of comfort
+// ranges defined for it's room. Comfort ranges are compared to values as received, temperatures in °C.
+// These values are rendered in red. Caller has to hold the lock.
+func (renderer *IndoorClimateRenderer) applyComfortRanges(roomId string, roomClimate *indoorCliemate) {
+
+	comfortRanges := renderer.roomCfg.rooms[roomId].comfortRanges
+	if comfort, ok := comfortRanges[events.MeasurementType_TEMPERATURE]; ok {
+		if roomClimate.temperature != nil && !comfort.contains(*roomClimate.temperature) {
+			roomClimate.TemperatureAlert = true
+			roomClimate.TemperatureColor = COLOR_RED
+		}
+	}
+	if comfort, ok := comfortRanges[events.MeasurementType_HUMIDITY]; ok {
+		if roomClimate.humidity != nil && !comfort.contains(*roomClimate.humidity) {
+			roomClimate.HumidityAlert = true
+			roomClimate.HumidityColor = COLOR_RED
+		}
+	}
+	roomClimate.OutOfRange = roomClimate.TemperatureAlert || roomClimate.HumidityAlert
+}
+
+// RoomsOutOfRange returns number of rooms with temperature or humidity outside of their comfort range.
+func (renderer *IndoorClimateRenderer) RoomsOutOfRange() int {
+
+	if renderer.needsInit() {
+		renderer.initIndoorClimateData()
+	}
+
+	count := 0
+	for _, roomClimate := range renderer.sortedRoomClimateData() {
+		if roomClimate.OutOfRange {
+			count++
+		}
+	}
+	return count
+}
+
+// RoomClimate returns latest temperature and humidity of all rooms, in display order.
+func (renderer *IndoorClimateRenderer) RoomClimate() []RoomClimate {
+
+	if renderer.needsInit() {
+		renderer.initIndoorClimateData()
+	}
+
+	rooms := []RoomClimate{}
+	for _, roomClimate := range renderer.sortedRoomClimateData() {
+		rooms = append(rooms, RoomClimate{
+			RoomId:      roomClimate.roomId,
+			RoomName:    roomClimate.RoomName,
+			Temperature: roomClimate.temperature,
+			Humidity:    roomClimate.humidity,
+			Stale:       roomClimate.Stale,
+			LastUpdate:  roomClimate.LastUpdate,
+		})
+	}
+	return rooms
+}
+
+// DeviceBatteries returns latest battery level of all devices which are assigned to a room, in percent.
+func (renderer *IndoorClimateRenderer) DeviceBatteries() []DeviceBattery {
+
+	if renderer.needsInit() {
+		renderer.initIndoorClimateData()
+	}
+
+	renderer.lock.RLock()
+	defer renderer.lock.RUnlock()
+
+	batteries := []DeviceBattery{}
+	for deviceId, values := range renderer.deviceValues {
+		value, ok := values[events.MeasurementType_BATTERY]
+		if !ok {
+			continue
+		}
+		roomId := renderer.roomCfg.deviceMap[deviceId]
+		batteries = append(batteries, DeviceBattery{
+			DeviceId:   deviceId,
+			RoomId:     roomId,
+			RoomName:   renderer.roomCfg.rooms[roomId].Name,
+			Percent:    renderer.battery.percent(value.value),
+			LastUpdate: value.timestamp,
+		})
+	}
+	return batteries
+}
+
+// applyOfflineState flags passed room climate as offline if at least one of it's devices is offline.
+// Caller has to hold the lock.
+func (renderer *IndoorClimateRenderer) applyOfflineState(roomId string, roomClimate *indoorCliemate, now time.Time) {
+
+	roomClimate.OfflineDevices = []string{}
+	for _, device := range renderer.offlineDevices(now) {
+		if device.RoomId == roomId {
+			roomClimate.OfflineDevices = append(roomClimate.OfflineDevices, device.DeviceId)
+		}
+	}
+	roomClimate.Offline = len(roomClimate.OfflineDevices) > 0
+}
+
+// OfflineDevices returns all devices assigned to a room which haven't send data within offline period, sorted by device id.
+// Devices are never offline if no offline period has been configured.
+func (renderer *IndoorClimateRenderer) OfflineDevices() []OfflineDevice {
+
+	if renderer.needsInit() {
+		renderer.initIndoorClimateData()
+	}
+
+	renderer.lock.RLock()
+	defer renderer.lock.RUnlock()
+
+	return renderer.offlineDevices(time.Now())
+}
+
+// offlineDevices returns all devices assigned to a room which haven't send data within offline period
+// at passed time, sorted by device id. Devices which never send data are treated as last seen at start
+// of this renderer. Caller has to hold the lock.
+func (renderer *IndoorClimateRenderer) offlineDevices(now time.Time) []OfflineDevice {
+
+	devices := []OfflineDevice{}
+	if renderer.offlineAfter <= 0 {
+		return devices
+	}
+	for deviceId, roomId := range renderer.roomCfg.deviceMap {
+		device := OfflineDevice{DeviceId: deviceId, RoomId: roomId, RoomName: renderer.roomCfg.rooms[roomId].Name}
+		lastSeen, ok := renderer.deviceLastSeen[deviceId]
+		if !ok {
+			lastSeen = renderer.startTime
+		}
+		if now.Sub(lastSeen) <= renderer.offlineAfter {
+			continue
+		}
+		if ok {
+			device.LastSeen = &lastSeen
+		}
+		devices = append(devices, device)
+	}
+	sort.Slice(devices, func(i, j int) bool {
+		return devices[i].DeviceId < devices[j].DeviceId
+	})
+	return devices
+}
+
+// applyStaleState sets last update of passed room climate and marks all values which exceed
+// max age of their measurement type as stale. Caller has to hold the lock.
+func (renderer *IndoorClimateRenderer) applyStaleState(roomId string, roomClimate *indoorCliemate, now time.Time) {
+
+	for measurementType, lastUpdate := range renderer.lastUpdates[roomId] {
+		if lastUpdate.After(roomClimate.LastUpdate) {
+			roomClimate.LastUpdate = lastUpdate
+		}
+		maxAge, ok := renderer.maxAge[measurementType]
+		if !ok || now.Sub(lastUpdate) <= maxAge {
+			continue
+		}
+		roomClimate.Stale = true
+		switch measurementType {
+		case events.MeasurementType_TEMPERATURE:
+			roomClimate.TemperatureColor = renderer.staleColor
+		case events.MeasurementType_HUMIDITY:
+			roomClimate.HumidityColor = renderer.staleColor
+		case events.MeasurementType_BATTERY:
+			roomClimate.BatteryIconColor = renderer.staleColor
+		}
+	}
 }
 
 // getRoomClimate will have a look if there's already climate data for given room.
@@ -154,13 +662,30 @@ func (renderer *IndoorClimateRenderer) getRoomClimate(roomId string) indoorCliem
 	}
 
 	roomClimate := indoorCliemate{
-		DisplayIndex:     "0",
-		Temperature:      "--",
-		Humidity:         "--",
-		BatteryIcon:      BATTERY_LEVEL_0_4,
-		BatteryIconColor: COLOR_BLACK,
-		RoomName:         "Room",
-		Anchor:           core.Point{X: 0, Y: 0},
+		roomId:               roomId,
+		DisplayIndex:         "0",
+		Temperature:          "--",
+		TemperatureMin:       "--",
+		TemperatureMax:       "--",
+		TemperatureColor:     COLOR_BLACK,
+		Humidity:             "--",
+		HumidityMin:          "--",
+		HumidityMax:          "--",
+		HumidityColor:        COLOR_BLACK,
+		TemperatureTrend:     TREND_STEADY,
+		TemperatureTrendIcon: TREND_ICON_STEADY,
+		DewPoint:             "--",
+		AbsoluteHumidity:     "--",
+		HeatIndex:            "--",
+		MoldRisk:             "--",
+		MoldRiskLevel:        MOLD_RISK_OK,
+		HumidityTrend:        TREND_STEADY,
+		HumidityTrendIcon:    TREND_ICON_STEADY,
+		BatteryIcon:          BATTERY_LEVEL_0_4,
+		BatteryIconColor:     COLOR_BLACK,
+		RoomName:             "Room",
+		Anchor:               core.Point{X: 0, Y: 0},
+		Slots:                renderer.defaultSlots(),
 	}
 	if roomCfg, ok := renderer.roomCfg.rooms[roomId]; ok {
 		roomClimate.DisplayIndex = roomCfg.DisplayIndex
@@ -169,15 +694,34 @@ func (renderer *IndoorClimateRenderer) getRoomClimate(roomId string) indoorCliem
 	return roomClimate
 }
 
-// sortedRoomClimateData sorts current room climate based on displayIndex given by room config.
+// sortedRoomClimateData returns a copy of current room climate, sorted by configured order and pinned positions.
+// Values which exceed their max age are marked as stale. If all rooms should be rendered, rooms without climate
+// data are added with placeholder values.
 func (renderer *IndoorClimateRenderer) sortedRoomClimateData() []indoorCliemate {
 
+	renderer.lock.RLock()
+	defer renderer.lock.RUnlock()
+
+	roomClimateData := renderer.roomClimate
+	if renderer.allRooms {
+		roomClimateData = make(map[string]indoorCliemate)
+		for roomId := range renderer.roomCfg.rooms {
+			roomClimateData[roomId] = renderer.getRoomClimate(roomId)
+		}
+	}
+
+	now := time.Now()
 	roomClimate := []indoorCliemate{}
-	for _, cliamte := range renderer.roomClimate {
+	for roomId, cliamte := range roomClimateData {
+		renderer.applyComfortRanges(roomId, &cliamte)
+		renderer.applyStaleState(roomId, &cliamte, now)
+		renderer.applyDailyExtremes(roomId, &cliamte, now)
+		renderer.applyTrends(roomId, &cliamte, now)
+		renderer.applyDerivedValues(&cliamte)
+		renderer.applyMoldRisk(roomId, &cliamte, now)
+		renderer.applyOfflineState(roomId, &cliamte, now)
+		cliamte.SlotsText = slotsText(cliamte.Slots)
 		roomClimate = append(roomClimate, cliamte)
 	}
-	sort.Slice(roomClimate, func(i, j int) bool {
-		return roomClimate[i].DisplayIndex < roomClimate[j].DisplayIndex
-	})
-	return roomClimate
+	return sortRoomClimate(roomClimate, renderer.order, renderer.roomCfg.rooms)
 }
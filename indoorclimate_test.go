@@ -2,10 +2,16 @@ package syncsign
 
 import (
 	"context"
+	"fmt"
+	"github.com/golang/protobuf/proto"
 	"github.com/stretchr/testify/suite"
 	events "github.com/tommzn/hdb-events-go"
+	core "github.com/tommzn/hdb-renderer-core"
 	"google.golang.org/protobuf/types/known/timestamppb"
+	"math"
+	"sort"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 )
@@ -24,7 +30,7 @@ func (suite *IndoorClimateTestSuite) TestGenerateContent() {
 
 	content, err := renderer.Content()
 	suite.Nil(err)
-	assertTemplateHash(suite.Assert(), content, "435a6dcd68d91da733c5a3208e1a36a627d977de")
+	assertTemplateHash(suite.Assert(), content, "46985fecd310ecd6bfa0068953b3311f68ff77b4")
 }
 
 func (suite *IndoorClimateTestSuite) TestGenerateContentWithError() {
@@ -51,7 +57,7 @@ func (suite *IndoorClimateTestSuite) TestDataSourceObserving() {
 
 	content, err := renderer.Content()
 	suite.Nil(err)
-	assertTemplateHash(suite.Assert(), content, "435a6dcd68d91da733c5a3208e1a36a627d977de")
+	assertTemplateHash(suite.Assert(), content, "46985fecd310ecd6bfa0068953b3311f68ff77b4")
 
 	ctx, cancelFunc := context.WithCancel(context.Background())
 	go renderer.ObserveDataSource(ctx)
@@ -71,7 +77,7 @@ func (suite *IndoorClimateTestSuite) TestDataSourceObserving() {
 	content2, err2 := renderer.Content()
 	suite.Nil(err2)
 	suite.True(strings.Contains(content2, newTemperature))
-	assertTemplateHash(suite.Assert(), content2, "4f53a5c96964883fbc9c4436d84cc1b73e60e70c")
+	assertTemplateHash(suite.Assert(), content2, "d39981776f44ad0071ff49efa628fffe73565272")
 
 	cancelFunc()
 }
@@ -109,7 +115,7 @@ func (suite *IndoorClimateTestSuite) TestStopDataSourceObservingOnClosedChannel(
 	}()
 	time.Sleep(100 * time.Millisecond)
 
-	close(renderer.datasource.(*datasourceMock).eventChan)
+	renderer.datasource.(*datasourceMock).closeMessageChannel()
 	select {
 	case ok := <-endChan:
 		suite.True(ok)
@@ -117,3 +123,421 @@ func (suite *IndoorClimateTestSuite) TestStopDataSourceObservingOnClosedChannel(
 		suite.T().Error("DataSource observing doesn't end as expected!")
 	}
 }
+
+func (suite *IndoorClimateTestSuite) TestConcurrentObserveAndRender() {
+
+	renderer := indoorClimateRendererForTest("fixtures/testconfig02.yml")
+	ctx, cancelFunc := context.WithCancel(context.Background())
+	defer cancelFunc()
+	go renderer.ObserveDataSource(ctx)
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				_, err := renderer.Content()
+				suite.Nil(err)
+			}
+		}()
+	}
+	for i := 0; i < 20; i++ {
+		renderer.datasource.(*datasourceMock).writeToMessageChannel(&events.IndoorClimate{
+			Timestamp: timestamppb.New(time.Now()),
+			DeviceId:  "Device2",
+			Type:      events.MeasurementType_TEMPERATURE,
+			Value:     fmt.Sprintf("2%d.5", i%10),
+		})
+	}
+	wg.Wait()
+}
+
+func (suite *IndoorClimateTestSuite) TestStaleMeasurements() {
+
+	renderer := indoorClimateRendererForTest("fixtures/testconfig08.yml")
+	ctx, cancelFunc := context.WithCancel(context.Background())
+	defer cancelFunc()
+	go renderer.ObserveDataSource(ctx)
+	time.Sleep(500 * time.Millisecond)
+
+	roomClimate := renderer.sortedRoomClimateData()
+	suite.Len(roomClimate, 2)
+	for _, climate := range roomClimate {
+		suite.False(climate.Stale)
+		suite.False(climate.LastUpdate.IsZero())
+		suite.Equal(COLOR_BLACK, climate.TemperatureColor)
+		suite.Equal(COLOR_BLACK, climate.HumidityColor)
+	}
+
+	lastUpdate := time.Now().Add(-1 * time.Hour)
+	for measurementType, value := range map[events.MeasurementType]string{events.MeasurementType_TEMPERATURE: "21.5", events.MeasurementType_HUMIDITY: "55"} {
+		renderer.datasource.(*datasourceMock).writeToMessageChannel(&events.IndoorClimate{
+			Timestamp: timestamppb.New(lastUpdate),
+			DeviceId:  "Device3",
+			Type:      measurementType,
+			Value:     value,
+		})
+	}
+	time.Sleep(500 * time.Millisecond)
+
+	roomClimate2 := renderer.sortedRoomClimateData()
+	suite.Len(roomClimate2, 3)
+	staleClimate := roomClimate2[2]
+	suite.Equal("Room3", staleClimate.RoomName)
+	suite.Equal("21.5", staleClimate.Temperature)
+	suite.True(staleClimate.Stale)
+	suite.Equal(lastUpdate.Unix(), staleClimate.LastUpdate.Unix())
+	suite.Equal(COLOR_BLACK, staleClimate.TemperatureColor)
+	suite.Equal(COLOR_WHITE, staleClimate.HumidityColor)
+
+	content, err := renderer.Content()
+	suite.Nil(err)
+	suite.True(strings.Contains(content, "\"textColor\": \"WHITE\""))
+	suite.True(strings.Contains(content, "\"text\": \"\\uf017\""))
+}
+
+func (suite *IndoorClimateTestSuite) TestGridLayout() {
+
+	renderer := indoorClimateRendererForTest("fixtures/testconfig02.yml")
+	renderer.originAnchor = core.Point{X: 10, Y: 20}
+	renderer.size = core.Size{Width: 100, Height: 50}
+	renderer.spacing = core.Spacing{Top: 1, Left: 2, Right: 3, Bottom: 4}
+
+	suite.Equal(core.Point{X: 10, Y: 20}, renderer.anchorForElement(renderer.originAnchor, 0, 5))
+	suite.Equal(core.Point{X: 430, Y: 20}, renderer.anchorForElement(renderer.originAnchor, 4, 5))
+
+	renderer.layout = gridLayout{columns: 2, direction: LAYOUT_HORIZONTAL}
+	suite.Equal(core.Point{X: 115, Y: 20}, renderer.anchorForElement(renderer.originAnchor, 1, 5))
+	suite.Equal(core.Point{X: 10, Y: 75}, renderer.anchorForElement(renderer.originAnchor, 2, 5))
+	suite.Equal(core.Point{X: 10, Y: 130}, renderer.anchorForElement(renderer.originAnchor, 4, 5))
+
+	renderer.layout = gridLayout{columns: 2, direction: LAYOUT_VERTICAL}
+	suite.Equal(core.Point{X: 10, Y: 75}, renderer.anchorForElement(renderer.originAnchor, 1, 5))
+	suite.Equal(core.Point{X: 10, Y: 130}, renderer.anchorForElement(renderer.originAnchor, 2, 5))
+	suite.Equal(core.Point{X: 115, Y: 20}, renderer.anchorForElement(renderer.originAnchor, 3, 5))
+
+	renderer.layout = gridLayout{columns: 0, direction: LAYOUT_VERTICAL}
+	suite.Equal(core.Point{X: 10, Y: 240}, renderer.anchorForElement(renderer.originAnchor, 4, 5))
+}
+
+func (suite *IndoorClimateTestSuite) TestDailyExtremes() {
+
+	renderer := indoorClimateRendererForTest("fixtures/testconfig08.yml")
+	suite.Equal("Europe/Berlin", renderer.location.String())
+
+	now := time.Now().In(renderer.location)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 30, 0, 0, renderer.location)
+	yesterday := today.Add(-1 * time.Hour)
+	messages := []*events.IndoorClimate{
+		&events.IndoorClimate{Timestamp: timestamppb.New(yesterday), DeviceId: "Device3", Type: events.MeasurementType_TEMPERATURE, Value: "14.2"},
+		&events.IndoorClimate{Timestamp: timestamppb.New(yesterday), DeviceId: "Device3", Type: events.MeasurementType_HUMIDITY, Value: "71"},
+		&events.IndoorClimate{Timestamp: timestamppb.New(today), DeviceId: "Device3", Type: events.MeasurementType_TEMPERATURE, Value: "20.5"},
+		&events.IndoorClimate{Timestamp: timestamppb.New(today.Add(1 * time.Minute)), DeviceId: "Device3", Type: events.MeasurementType_TEMPERATURE, Value: "19.25"},
+		&events.IndoorClimate{Timestamp: timestamppb.New(today.Add(2 * time.Minute)), DeviceId: "Device3", Type: events.MeasurementType_TEMPERATURE, Value: "21"},
+		&events.IndoorClimate{Timestamp: timestamppb.New(today.Add(3 * time.Minute)), DeviceId: "Device3", Type: events.MeasurementType_TEMPERATURE, Value: "20.1"},
+	}
+	for _, message := range messages {
+		renderer.addAsIndoorClimateData(message)
+	}
+
+	roomClimate := renderer.sortedRoomClimateData()
+	suite.Len(roomClimate, 1)
+	suite.Equal("20.1", roomClimate[0].Temperature)
+	suite.Equal("19.2", roomClimate[0].TemperatureMin)
+	suite.Equal("21.0", roomClimate[0].TemperatureMax)
+	suite.Equal("71", roomClimate[0].Humidity)
+	suite.Equal("--", roomClimate[0].HumidityMin)
+	suite.Equal("--", roomClimate[0].HumidityMax)
+}
+
+func (suite *IndoorClimateTestSuite) TestTrends() {
+
+	renderer := indoorClimateRendererForTest("fixtures/testconfig08.yml")
+
+	now := time.Now()
+	messages := []*events.IndoorClimate{
+		&events.IndoorClimate{Timestamp: timestamppb.New(now.Add(-50 * time.Minute)), DeviceId: "Device3", Type: events.MeasurementType_TEMPERATURE, Value: "25.0"},
+		&events.IndoorClimate{Timestamp: timestamppb.New(now.Add(-25 * time.Minute)), DeviceId: "Device3", Type: events.MeasurementType_TEMPERATURE, Value: "20.0"},
+		&events.IndoorClimate{Timestamp: timestamppb.New(now.Add(-24 * time.Minute)), DeviceId: "Device3", Type: events.MeasurementType_HUMIDITY, Value: "60"},
+		&events.IndoorClimate{Timestamp: timestamppb.New(now.Add(-10 * time.Minute)), DeviceId: "Device3", Type: events.MeasurementType_TEMPERATURE, Value: "20.5"},
+		&events.IndoorClimate{Timestamp: timestamppb.New(now.Add(-5 * time.Minute)), DeviceId: "Device3", Type: events.MeasurementType_HUMIDITY, Value: "56"},
+	}
+	for _, message := range messages {
+		renderer.addAsIndoorClimateData(message)
+	}
+
+	roomClimate := renderer.sortedRoomClimateData()
+	suite.Len(roomClimate, 1)
+	suite.Equal(TREND_RISING, roomClimate[0].TemperatureTrend)
+	suite.Equal(TREND_ICON_RISING, roomClimate[0].TemperatureTrendIcon)
+	suite.Equal(TREND_STEADY, roomClimate[0].HumidityTrend)
+	suite.Equal(TREND_ICON_STEADY, roomClimate[0].HumidityTrendIcon)
+
+	renderer.addAsIndoorClimateData(&events.IndoorClimate{Timestamp: timestamppb.New(now.Add(-1 * time.Minute)), DeviceId: "Device3", Type: events.MeasurementType_HUMIDITY, Value: "54.5"})
+	renderer.addAsIndoorClimateData(&events.IndoorClimate{Timestamp: timestamppb.New(now), DeviceId: "Device3", Type: events.MeasurementType_TEMPERATURE, Value: "19.9"})
+
+	roomClimate2 := renderer.sortedRoomClimateData()
+	suite.Equal(TREND_STEADY, roomClimate2[0].TemperatureTrend)
+	suite.Equal(TREND_FALLING, roomClimate2[0].HumidityTrend)
+	suite.Equal(TREND_ICON_FALLING, roomClimate2[0].HumidityTrendIcon)
+}
+
+func (suite *IndoorClimateTestSuite) TestComfortRanges() {
+
+	renderer := indoorClimateRendererForTest("fixtures/testconfig09.yml")
+	suite.Equal(1, renderer.RoomsOutOfRange())
+
+	roomClimate := renderer.sortedRoomClimateData()
+	suite.Len(roomClimate, 2)
+	suite.Equal("Bedroom", roomClimate[0].RoomName)
+	suite.True(roomClimate[0].OutOfRange)
+	suite.True(roomClimate[0].TemperatureAlert)
+	suite.Equal(COLOR_RED, roomClimate[0].TemperatureColor)
+	suite.True(roomClimate[0].HumidityAlert)
+	suite.Equal(COLOR_RED, roomClimate[0].HumidityColor)
+	suite.False(roomClimate[1].OutOfRange)
+	suite.Equal(COLOR_BLACK, roomClimate[1].TemperatureColor)
+
+	renderer.addAsIndoorClimateData(&events.IndoorClimate{Timestamp: timestamppb.New(time.Now().Add(1 * time.Minute)), DeviceId: "Device1", Type: events.MeasurementType_TEMPERATURE, Value: "25.1"})
+	roomClimate2 := renderer.sortedRoomClimateData()
+	suite.True(roomClimate2[1].OutOfRange)
+	suite.True(roomClimate2[1].TemperatureAlert)
+	suite.False(roomClimate2[1].HumidityAlert)
+
+	var summary ComfortSummary = renderer
+	suite.NotNil(summary)
+}
+
+func (suite *IndoorClimateTestSuite) TestUnmappedDevices() {
+
+	renderer := indoorClimateRendererForTest("fixtures/testconfig02.yml")
+	suite.Len(renderer.UnmappedDevices(), 0)
+
+	now := time.Now()
+	messages := []*events.IndoorClimate{
+		&events.IndoorClimate{Timestamp: timestamppb.New(now), DeviceId: "Device4", Type: events.MeasurementType_TEMPERATURE, Value: "21.5"},
+		&events.IndoorClimate{Timestamp: timestamppb.New(now.Add(1 * time.Minute)), DeviceId: "Device4", Type: events.MeasurementType_HUMIDITY, Value: "51"},
+		&events.IndoorClimate{Timestamp: timestamppb.New(now.Add(-1 * time.Minute)), DeviceId: "Device4", Type: events.MeasurementType_HUMIDITY, Value: "49"},
+		&events.IndoorClimate{Timestamp: timestamppb.New(now), DeviceId: "Device3", Type: events.MeasurementType_BATTERY, Value: "87"},
+	}
+	for _, message := range messages {
+		renderer.addAsIndoorClimateData(message)
+	}
+
+	devices := renderer.UnmappedDevices()
+	suite.Len(devices, 2)
+	suite.Equal("Device3", devices[0].DeviceId)
+	suite.Equal(map[string]string{"battery": "87"}, devices[0].Values)
+	suite.Equal("Device4", devices[1].DeviceId)
+	suite.Equal(now.Add(1*time.Minute).Unix(), devices[1].LastSeen.Unix())
+	suite.Equal(map[string]string{"temperature": "21.5", "humidity": "51"}, devices[1].Values)
+
+	devices[1].Values["humidity"] = "0"
+	suite.Equal("51", renderer.UnmappedDevices()[1].Values["humidity"])
+
+	var reporter DeviceReporter = renderer
+	suite.NotNil(reporter)
+}
+
+func (suite *IndoorClimateTestSuite) TestRenderAllRooms() {
+
+	renderer := indoorClimateRendererForTest("fixtures/testconfig11.yml")
+	suite.True(renderer.allRooms)
+
+	content, err := renderer.Content()
+	suite.Nil(err)
+	suite.True(strings.Contains(content, "Room3"))
+
+	roomClimate := renderer.sortedRoomClimateData()
+	suite.Len(roomClimate, 3)
+	suite.Equal("Room3", roomClimate[0].RoomName)
+	suite.Equal("--", roomClimate[0].Temperature)
+	suite.Equal("--", roomClimate[0].Humidity)
+	suite.Equal("Room1", roomClimate[1].RoomName)
+	suite.Equal("17.1", roomClimate[1].Temperature)
+
+	renderer2 := indoorClimateRendererWithDataSourceErrorForTest("fixtures/testconfig11.yml")
+	content2, err2 := renderer2.Content()
+	suite.Nil(err2)
+	suite.True(strings.Contains(content2, "Room1"))
+	suite.Len(renderer2.sortedRoomClimateData(), 3)
+}
+
+func (suite *IndoorClimateTestSuite) TestShareRoomClimate() {
+
+	renderer := indoorClimateRendererForTest("fixtures/testconfig11.yml")
+
+	roomClimate := renderer.RoomClimate()
+	suite.Len(roomClimate, 3)
+	suite.Equal("3", roomClimate[0].RoomId)
+	suite.Nil(roomClimate[0].Temperature)
+	suite.Nil(roomClimate[0].Humidity)
+	suite.Equal("Room1", roomClimate[1].RoomName)
+	suite.Equal(17.1, *roomClimate[1].Temperature)
+	suite.Equal(65.0, *roomClimate[1].Humidity)
+	suite.False(roomClimate[1].Stale)
+}
+
+func (suite *IndoorClimateTestSuite) TestCalibrationAndUnit() {
+
+	renderer := indoorClimateRendererForTest("fixtures/testconfig15.yml")
+	renderer.initIndoorClimateData()
+
+	roomClimate := renderer.sortedRoomClimateData()
+	suite.Len(roomClimate, 2)
+	suite.Equal("Room1", roomClimate[0].RoomName)
+	suite.Equal(16.3, math.Round(*roomClimate[0].temperature*10)/10)
+	suite.Equal("61.34", roomClimate[0].Temperature)
+	suite.Equal("61.34", roomClimate[0].TemperatureMax)
+	suite.Equal("69.5", roomClimate[0].Humidity)
+	suite.False(roomClimate[0].TemperatureAlert)
+	suite.Equal("74.30", roomClimate[1].Temperature)
+	suite.Equal("57.0", roomClimate[1].Humidity)
+
+	renderer.addAsIndoorClimateData(indoorClimateForTest("Device2", events.MeasurementType_TEMPERATURE, "18", time.Now()))
+	roomClimate2 := renderer.sortedRoomClimateData()
+	suite.Equal("62.96", roomClimate2[0].Temperature)
+	suite.Equal("61.34", roomClimate2[0].TemperatureMin)
+	suite.True(roomClimate2[0].TemperatureAlert)
+}
+
+func (suite *IndoorClimateTestSuite) TestRoomAnchorAndTemplate() {
+
+	roomTemplates := map[string]core.Template{"1": templateQithFileForTest("templates/indoorclimate_tile.json")}
+	renderer := indoorClimateRendererWithTemplatesForTest("fixtures/testconfig16.yml", IndoorClimateTemplates{Room: templateForTest(), Rooms: roomTemplates})
+
+	items, err := itemsFromRenderer(renderer)
+	suite.Nil(err)
+	suite.Len(items, 9)
+
+	tile := items[0].Data.(*TextData)
+	suite.Equal("hdb.indoorclimate.tile.1", tile.Id)
+	suite.Equal("17.1° 65%", tile.Text)
+	suite.Equal(Block{X: 300, Y: 250, W: 110, H: 30}, tile.Block)
+
+	roomName := items[1].Data.(*TextData)
+	suite.Equal("Room1", roomName.Text)
+	suite.Equal(280, roomName.Block.Y)
+
+	temperature := items[2].Data.(*TextData)
+	suite.Equal("hdb.indoorclimate.temp.2", temperature.Id)
+	suite.Equal(10, temperature.Block.X)
+	suite.Equal(20, temperature.Block.Y)
+}
+
+func (suite *IndoorClimateTestSuite) TestRoomGroups() {
+
+	templates := IndoorClimateTemplates{Room: templateForTest(), GroupHeader: templateQithFileForTest("templates/indoorclimate_header.json")}
+	renderer := indoorClimateRendererWithTemplatesForTest("fixtures/testconfig17.yml", templates)
+	suite.NotNil(renderer.headerTemplate)
+	suite.Equal(40, renderer.headerHeight)
+
+	items, err := itemsFromRenderer(renderer)
+	suite.Nil(err)
+	suite.Len(items, 37)
+
+	assertItem := func(item Item, id, text string, x, y int) {
+		textData := item.Data.(*TextData)
+		suite.Equal(id, textData.Id)
+		if text != "" {
+			suite.Equal(text, textData.Text)
+		}
+		suite.Equal(x, textData.Block.X, id)
+		suite.Equal(y, textData.Block.Y, id)
+	}
+	assertItem(items[0], "hdb.indoorclimate.group.0", "Upstairs", 10, 10)
+	assertItem(items[1], "hdb.indoorclimate.temp.2", "23.5°", 10, 60)
+	assertItem(items[5], "hdb.indoorclimate.temp.range.2", "23.5° / 23.5°", 10, 145)
+	assertItem(items[8], "hdb.indoorclimate.temp.3", "--°", 170, 60)
+	assertItem(items[15], "hdb.indoorclimate.temp.4", "", 10, 170)
+	assertItem(items[22], "hdb.indoorclimate.group.1", "Ground floor", 10, 270)
+	assertItem(items[23], "hdb.indoorclimate.temp.1", "17.1°", 10, 320)
+	assertItem(items[30], "hdb.indoorclimate.temp.5", "", 10, 430)
+}
+
+func (suite *IndoorClimateTestSuite) TestRoomGroupsWithoutHeaderTemplate() {
+
+	renderer := indoorClimateRendererForTest("fixtures/testconfig17.yml")
+	suite.Nil(renderer.headerTemplate)
+
+	_, err := renderer.Content()
+	suite.NotNil(err)
+}
+
+func (suite *IndoorClimateTestSuite) TestDeviceBatteries() {
+
+	renderer := indoorClimateRendererForTest("fixtures/testconfig02.yml")
+
+	batteries := renderer.DeviceBatteries()
+	suite.Len(batteries, 2)
+	sort.Slice(batteries, func(i, j int) bool {
+		return batteries[i].DeviceId < batteries[j].DeviceId
+	})
+	suite.Equal("DEVICE1", batteries[0].DeviceId)
+	suite.Equal("2", batteries[0].RoomId)
+	suite.Equal("Room2", batteries[0].RoomName)
+	suite.Equal(97.0, batteries[0].Percent)
+	suite.Equal(23.0, batteries[1].Percent)
+}
+
+func (suite *IndoorClimateTestSuite) TestOfflineDevices() {
+
+	renderer := indoorClimateRendererForTest("fixtures/testconfig19.yml")
+	suite.Equal(1*time.Hour, renderer.offlineAfter)
+	renderer.startTime = time.Now().Add(-2 * time.Hour)
+
+	devices := renderer.OfflineDevices()
+	suite.Len(devices, 2)
+	suite.Equal(OfflineDevice{DeviceId: "DEVICE3", RoomId: "3", RoomName: "Room3"}, devices[0])
+	suite.Equal("DEVICE4", devices[1].DeviceId)
+	suite.Nil(devices[1].LastSeen)
+
+	lastSeen := time.Now().Add(-2 * time.Hour)
+	renderer.addAsIndoorClimateData(indoorClimateForTest("Device4", events.MeasurementType_TEMPERATURE, "21", lastSeen))
+	renderer.addAsIndoorClimateData(indoorClimateForTest("Device3", events.MeasurementType_BATTERY, "80", time.Now()))
+
+	offlineDevices := renderer.offlineDevices(time.Now())
+	suite.Len(offlineDevices, 1)
+	suite.Equal("DEVICE4", offlineDevices[0].DeviceId)
+	suite.True(lastSeen.Equal(*offlineDevices[0].LastSeen))
+
+	roomClimate := renderer.sortedRoomClimateData()
+	suite.Len(roomClimate, 3)
+	suite.False(roomClimate[0].Offline)
+	suite.Len(roomClimate[0].OfflineDevices, 0)
+	suite.Equal("Room3", roomClimate[2].RoomName)
+	suite.True(roomClimate[2].Offline)
+	suite.Equal([]string{"DEVICE4"}, roomClimate[2].OfflineDevices)
+
+	renderer2 := indoorClimateRendererForTest("fixtures/testconfig02.yml")
+	suite.Len(renderer2.OfflineDevices(), 0)
+}
+
+func (suite *IndoorClimateTestSuite) TestNoOfflineDevicesAfterStart() {
+
+	renderer := indoorClimateRendererWithoutDataForTest("fixtures/testconfig19.yml")
+
+	suite.Len(renderer.OfflineDevices(), 0)
+	suite.Len(renderer.offlineDevices(time.Now().Add(2*time.Hour)), 4)
+}
+
+func (suite *IndoorClimateTestSuite) TestOfflineIndicator() {
+
+	renderer := indoorClimateRendererForTest("fixtures/testconfig19.yml")
+	renderer.startTime = time.Now().Add(-2 * time.Hour)
+	renderer.initIndoorClimateData()
+	renderer.addAsIndoorClimateData(indoorClimateForTest("Device4", events.MeasurementType_TEMPERATURE, "21", time.Now().Add(-2*time.Hour)))
+	renderer.dataSourceChan = make(chan proto.Message)
+
+	items, err := itemsFromRenderer(renderer)
+	suite.Nil(err)
+	suite.Len(items, 21)
+	battery1 := items[2].Data.(*TextData)
+	suite.Equal("hdb.indoorclimate.battery.1", battery1.Id)
+	suite.Equal(string(BATTERY_LEVEL_1_4), battery1.Text)
+	battery3 := items[16].Data.(*TextData)
+	suite.Equal("hdb.indoorclimate.battery.3", battery3.Id)
+	suite.Equal("", battery3.Text)
+	suite.Equal(COLOR_RED, battery3.TextColor)
+}
@@ -0,0 +1,62 @@
+package syncsign
+
+import (
+	events "github.com/tommzn/hdb-events-go"
+)
+
+// ItemRenderer generates SyncSign items instead of raw JSON content.
+type ItemRenderer interface {
+
+	// Items returns all generated items.
+	Items() ([]Item, error)
+}
+
+// VolatileRenderer generates items which change on each request, e.g. a timestamp.
+type VolatileRenderer interface {
+
+	// IsVolatile returns true if generated items should not be used to detect content changes.
+	IsVolatile() bool
+}
+
+// ComfortSummary provides the number of rooms with values outside of their comfort range,
+// e.g. to display an alert in other widgets.
+type ComfortSummary interface {
+
+	// RoomsOutOfRange returns number of rooms with temperature or humidity outside of their comfort range.
+	RoomsOutOfRange() int
+}
+
+// DeviceReporter provides devices which send data but aren't assigned by config.
+type DeviceReporter interface {
+
+	// UnmappedDevices returns all devices which aren't assigned to a room.
+	UnmappedDevices() []UnmappedDevice
+}
+
+// IndoorClimateProvider shares indoor climate observed by a renderer with other widgets.
+type IndoorClimateProvider interface {
+
+	// RoomClimate returns latest temperature and humidity of all rooms, in display order.
+	RoomClimate() []RoomClimate
+}
+
+// WeatherProvider shares outdoor weather observed by a renderer with other widgets.
+type WeatherProvider interface {
+
+	// CurrentWeather returns latest current weather, nil if there's no weather data.
+	CurrentWeather() *events.CurrentWeather
+}
+
+// BatteryReporter provides battery status of devices observed by a renderer.
+type BatteryReporter interface {
+
+	// DeviceBatteries returns latest battery level of all devices which are assigned to a room.
+	DeviceBatteries() []DeviceBattery
+}
+
+// OfflineDeviceReporter provides devices which haven't send data for a while.
+type OfflineDeviceReporter interface {
+
+	// OfflineDevices returns all devices assigned to a room which are offline.
+	OfflineDevices() []OfflineDevice
+}
@@ -0,0 +1,107 @@
+package syncsign
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+
+	core "github.com/tommzn/hdb-renderer-core"
+)
+
+// UnmarshalJSON decodes item data depending on item type.
+// Data of unknown item types or with fields the item model doesn't know will be kept as raw JSON.
+func (item *Item) UnmarshalJSON(data []byte) error {
+
+	rawItem := struct {
+		Type ItemType        `json:"type"`
+		Data json.RawMessage `json:"data"`
+	}{}
+	if err := json.Unmarshal(data, &rawItem); err != nil {
+		return err
+	}
+
+	item.Type = rawItem.Type
+	var itemData interface{}
+	switch rawItem.Type {
+	case ITEM_TEXT:
+		itemData = &TextData{}
+	case ITEM_RECTANGLE:
+		itemData = &RectangleData{}
+	case ITEM_QRCODE:
+		itemData = &QrCodeData{}
+	case ITEM_BOTTOM_CUSTOM_BUTTONS:
+		itemData = &ButtonsData{}
+	default:
+		item.Data = rawItem.Data
+		return nil
+	}
+	decoder := json.NewDecoder(bytes.NewReader(rawItem.Data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(itemData); err != nil {
+		if lenientErr := json.Unmarshal(rawItem.Data, itemData); lenientErr != nil {
+			return lenientErr
+		}
+		item.Data = rawItem.Data
+		return nil
+	}
+	item.Data = itemData
+	return nil
+}
+
+// ParseItems converts content generated by a template, a comma separated list of items, into items.
+// Leading and trailing separators will be ignored.
+func parseItems(content string) ([]Item, error) {
+
+	items := []Item{}
+	content = strings.TrimSpace(content)
+	content = strings.TrimPrefix(content, ",")
+	content = strings.TrimSuffix(content, ",")
+	if content == "" {
+		return items, nil
+	}
+	err := json.Unmarshal([]byte("["+content+"]"), &items)
+	return items, err
+}
+
+// MarshalItems converts passed items into a comma separated list of JSON objects.
+func marshalItems(items []Item) (string, error) {
+
+	contents := []string{}
+	for _, item := range items {
+		buf := &bytes.Buffer{}
+		encoder := json.NewEncoder(buf)
+		encoder.SetEscapeHTML(false)
+		if err := encoder.Encode(item); err != nil {
+			return "", err
+		}
+		contents = append(contents, strings.TrimSpace(buf.String()))
+	}
+	return strings.Join(contents, ","), nil
+}
+
+// ItemsFromRenderer returns items of passed renderer. If it doesn't implement ItemRenderer
+// generated content will be parsed.
+func itemsFromRenderer(renderer core.Renderer) ([]Item, error) {
+
+	if itemRenderer, ok := renderer.(ItemRenderer); ok {
+		return itemRenderer.Items()
+	}
+	return itemsFromContent(renderer)
+}
+
+// ItemsFromContent parses content of passed renderer into items.
+func itemsFromContent(renderer core.Renderer) ([]Item, error) {
+
+	content, err := renderer.Content()
+	if err != nil {
+		return []Item{}, err
+	}
+	return parseItems(content)
+}
+
+// ParseResponse converts passed response content into a response model.
+func ParseResponse(content string) (*Response, error) {
+	response := &Response{}
+	err := json.Unmarshal([]byte(content), response)
+	return response, err
+}
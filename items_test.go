@@ -0,0 +1,99 @@
+package syncsign
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/stretchr/testify/suite"
+	core "github.com/tommzn/hdb-renderer-core"
+	"testing"
+)
+
+type ItemsTestSuite struct {
+	suite.Suite
+}
+
+func TestItemsTestSuite(t *testing.T) {
+	suite.Run(t, new(ItemsTestSuite))
+}
+
+func (suite *ItemsTestSuite) TestParseTemplateContent() {
+
+	renderer := billingReportRendererForTest("fixtures/testconfig04.yml")
+
+	items, err := itemsFromRenderer(renderer)
+	suite.Nil(err)
+	suite.Len(items, 3)
+	suite.Equal(ITEM_TEXT, items[0].Type)
+	textData, ok := items[1].Data.(*TextData)
+	suite.True(ok)
+	suite.Equal("hdb.billingreport.period", textData.Id)
+	suite.Equal("Jan 2022", textData.Text)
+	suite.Equal(Block{X: 855, Y: 10, W: 95, H: 20}, textData.Block)
+	suite.Equal(&Offset{X: 5, Y: 0}, textData.Offset)
+
+	errorItems, err := itemsFromRenderer(NewErrorRenderer(templateQithFileForTest("templates/error.json"), errors.New("Error occured!")))
+	suite.Nil(err)
+	suite.Len(errorItems, 3)
+	rectangle, ok := errorItems[0].Data.(*RectangleData)
+	suite.True(ok)
+	suite.Equal(COLOR_WHITE, rectangle.FillColor)
+	suite.Equal(1, rectangle.StrokeThickness)
+}
+
+func (suite *ItemsTestSuite) TestParseItems() {
+
+	items, err := parseItems(" ,{\"type\": \"TEXT\", \"data\": {\"text\": \"\"}}, ")
+	suite.Nil(err)
+	suite.Len(items, 1)
+
+	emptyItems, err := parseItems("  ")
+	suite.Nil(err)
+	suite.Len(emptyItems, 0)
+
+	_, err = parseItems("{\"type\": \"TEXT\"")
+	suite.NotNil(err)
+}
+
+func (suite *ItemsTestSuite) TestMarshalItems() {
+
+	content := "{\"type\":\"TEXT\",\"data\":{\"text\":\"<>\",\"block\":{\"x\":1,\"y\":2,\"w\":3,\"h\":4}}}," +
+		"{\"type\":\"LINE\",\"data\":{\"x0\":1,\"y0\":2}}"
+	items, err := parseItems(content)
+	suite.Nil(err)
+	suite.Len(items, 2)
+	_, ok := items[1].Data.(json.RawMessage)
+	suite.True(ok)
+
+	marshaledItems, err := marshalItems(items)
+	suite.Nil(err)
+	suite.Equal(content, marshaledItems)
+
+	contentWithUnknownField := "{\"type\":\"TEXT\",\"data\":{\"text\":\"abc\",\"wrap\":true}}"
+	items2, err := parseItems(contentWithUnknownField)
+	suite.Nil(err)
+	_, ok = items2[0].Data.(json.RawMessage)
+	suite.True(ok)
+	marshaledItems2, err := marshalItems(items2)
+	suite.Nil(err)
+	suite.Equal(contentWithUnknownField, marshaledItems2)
+
+	_, err = parseItems("{\"type\":\"TEXT\",\"data\":{\"text\":1}}")
+	suite.NotNil(err)
+}
+
+func (suite *ItemsTestSuite) TestParseResponse() {
+
+	itemRenderer := newRendererMock(false, false)
+	renderer := NewResponseRenderer(templateQithFileForTest("templates/response.json"), "Node-1", []core.Renderer{itemRenderer})
+
+	response, err := renderer.(*ResponseRenderer).Response()
+	suite.Nil(err)
+	suite.Equal(200, response.Code)
+	suite.Len(response.Data, 1)
+	suite.Equal("Node-1", response.Data[0].NodeId)
+	suite.Equal(COLOR_WHITE, response.Data[0].Content.Background.BgColor)
+	suite.Len(response.Data[0].Content.Items, 2)
+
+	_, err = ParseResponse("{")
+	suite.NotNil(err)
+}
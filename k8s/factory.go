@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"path"
 	"sync"
 
 	config "github.com/tommzn/go-config"
@@ -18,12 +19,18 @@ func newFactory(conf config.Config, logger log.Logger, ctx context.Context) *fac
 		logger:           logger,
 		ctx:              ctx,
 		wg:               &sync.WaitGroup{},
+		templates:        make(map[string]core.Template),
+		widgetRenderer:   make(map[string]core.Renderer),
+		pendingWidgets:   make(map[string]bool),
 		responseRenderer: make(map[string]core.Renderer),
+		displayConfig:    syncsign.NewDisplayConfig(conf),
 		datasources:      []datasource.Client{},
 	}
 }
 
 func (f *factory) newResponseRendererTemplate() core.Template {
+	f.templateLock.Lock()
+	defer f.templateLock.Unlock()
 	if f.responseTemplate == nil {
 		f.responseTemplate = core.NewFileTemplateFromConfig(f.conf, "hdb.template_dir", "hdb.response.template")
 	}
@@ -31,47 +38,46 @@ func (f *factory) newResponseRendererTemplate() core.Template {
 }
 
 func (f *factory) newErrorRendererTemplate() core.Template {
+	f.templateLock.Lock()
+	defer f.templateLock.Unlock()
 	if f.errorTemplate == nil {
 		f.errorTemplate = core.NewFileTemplateFromConfig(f.conf, "hdb.template_dir", "hdb.error.template")
 	}
 	return f.errorTemplate
 }
 
-func (f *factory) newIndoorClimateTemplate() core.Template {
-	if f.indoorClimateTemplate == nil {
-		f.indoorClimateTemplate = core.NewFileTemplateFromConfig(f.conf, "hdb.template_dir", "hdb.indoorclimate.template")
+// newTemplate returns a template for the file defined by passed config key.
+// Templates are cached by config key.
+func (f *factory) newTemplate(templateConfigKey string) core.Template {
+	f.templateLock.Lock()
+	defer f.templateLock.Unlock()
+	if _, ok := f.templates[templateConfigKey]; !ok {
+		f.templates[templateConfigKey] = core.NewFileTemplateFromConfig(f.conf, "hdb.template_dir", templateConfigKey)
 	}
-	return f.indoorClimateTemplate
+	return f.templates[templateConfigKey]
+}
+
+// newTemplateFromFile returns a template for passed file in template directory defined by "hdb.template_dir".
+// Templates are cached by file path.
+func (f *factory) newTemplateFromFile(templateFile string) core.Template {
+	f.templateLock.Lock()
+	defer f.templateLock.Unlock()
+	templatePath := path.Join(*f.conf.Get("hdb.template_dir", config.AsStringPtr("templates")), templateFile)
+	if _, ok := f.templates[templatePath]; !ok {
+		f.templates[templatePath] = core.NewFileTemplate(templatePath)
+	}
+	return f.templates[templatePath]
 }
 
 func (f *factory) newTimestampTemplate() core.Template {
+	f.templateLock.Lock()
+	defer f.templateLock.Unlock()
 	if f.timestampTemplate == nil {
 		f.timestampTemplate = core.NewFileTemplateFromConfig(f.conf, "hdb.template_dir", "hdb.timestamp.template")
 	}
 	return f.timestampTemplate
 }
 
-func (f *factory) newBillingReportTemplate() core.Template {
-	if f.billingReportTemplate == nil {
-		f.billingReportTemplate = core.NewFileTemplateFromConfig(f.conf, "hdb.template_dir", "hdb.billingreport.template")
-	}
-	return f.billingReportTemplate
-}
-
-func (f *factory) newCurrentWeatherTemplate() core.Template {
-	if f.currentWeatherTemplate == nil {
-		f.currentWeatherTemplate = core.NewFileTemplateFromConfig(f.conf, "hdb.template_dir", "hdb.weather.template.current")
-	}
-	return f.currentWeatherTemplate
-}
-
-func (f *factory) newForeCastWeatherTemplate() core.Template {
-	if f.forecastWeatherTemplate == nil {
-		f.forecastWeatherTemplate = core.NewFileTemplateFromConfig(f.conf, "hdb.template_dir", "hdb.weather.template.forecast")
-	}
-	return f.forecastWeatherTemplate
-}
-
 func (f *factory) newTimestampRenderer() core.Renderer {
 	return syncsign.NewTimestampRenderer(f.newTimestampTemplate())
 }
@@ -88,46 +94,213 @@ func (f *factory) newErrorResponseRenderer(nodeId string, err error) core.Render
 	return syncsign.NewResponseRenderer(f.newResponseRendererTemplate(), nodeId, itemRenderer)
 }
 
+// newResponseRenderer returns a response renderer with all widgets defined for passed node.
+// Render ids are derived from content if "hdb.response.content_hash" is enabled.
 func (f *factory) newResponseRenderer(nodeId string) core.Renderer {
+	f.lock.Lock()
+	defer f.lock.Unlock()
 	if _, ok := f.responseRenderer[nodeId]; !ok {
-		itemRenderer := []core.Renderer{
-			f.newIndoorClimateRenderer(),
-			f.newBillingReportRenderer(),
-			f.newWeatherRenderer(),
-			f.newTimestampRenderer(),
+		itemRenderer := []core.Renderer{}
+		for _, widget := range f.newDisplayConfig().Widgets(nodeId) {
+			if renderer := f.newWidgetRenderer(widget); renderer != nil {
+				itemRenderer = append(itemRenderer, renderer)
+			}
+		}
+		if *f.conf.GetAsBool("hdb.response.content_hash", config.AsBoolPtr(false)) {
+			f.responseRenderer[nodeId] = syncsign.NewResponseRendererWithContentHash(f.newResponseRendererTemplate(), nodeId, itemRenderer)
+		} else {
+			f.responseRenderer[nodeId] = syncsign.NewResponseRenderer(f.newResponseRendererTemplate(), nodeId, itemRenderer)
 		}
-		f.responseRenderer[nodeId] = syncsign.NewResponseRenderer(f.newResponseRendererTemplate(), nodeId, itemRenderer)
 	}
 	return f.responseRenderer[nodeId]
 }
 
-func (f *factory) newIndoorClimateRenderer() core.Renderer {
-	if f.indoorClimateRenderer == nil {
-		renderer := syncsign.NewIndoorClimateRenderer(f.conf, f.logger, f.newIndoorClimateTemplate(), f.newDataSource())
-		go renderer.ObserveDataSource(f.ctx)
-		f.indoorClimateRenderer = renderer
+// newWidgetRenderer returns a renderer for passed widget, depending on it's type. Renderers are
+// shared by all displays which use the same widget. Nil is returned for unknown widget types and for widgets
+// which depend on themselves through the widgets they use as data provider. Caller has to hold the lock.
+func (f *factory) newWidgetRenderer(widget string) core.Renderer {
+	if _, ok := f.widgetRenderer[widget]; !ok {
+		if f.pendingWidgets[widget] {
+			f.logger.Errorf("Widget %s depends on itself.", widget)
+			return nil
+		}
+		f.pendingWidgets[widget] = true
+		defer delete(f.pendingWidgets, widget)
+
+		switch widgetType := syncsign.WidgetType(f.conf, widget); widgetType {
+		case syncsign.WIDGET_INDOORCLIMATE:
+			f.widgetRenderer[widget] = f.newIndoorClimateRenderer(widget)
+		case syncsign.WIDGET_BILLINGREPORT:
+			f.widgetRenderer[widget] = f.newBillingReportRenderer(widget)
+		case syncsign.WIDGET_WEATHER:
+			f.widgetRenderer[widget] = f.newWeatherRenderer(widget)
+		case syncsign.WIDGET_TIMESTAMP:
+			f.widgetRenderer[widget] = f.newTimestampRenderer()
+		case syncsign.WIDGET_VENTILATION:
+			f.widgetRenderer[widget] = f.newVentilationRenderer(widget)
+		case syncsign.WIDGET_LOWBATTERY:
+			f.widgetRenderer[widget] = f.newLowBatteryRenderer(widget)
+		case syncsign.WIDGET_COMFORT:
+			f.widgetRenderer[widget] = f.newComfortRenderer(widget)
+		default:
+			f.logger.Errorf("Unknown type %s for widget %s.", widgetType, widget)
+			return nil
+		}
 	}
-	return f.indoorClimateRenderer
+	return f.widgetRenderer[widget]
 }
 
-func (f *factory) newBillingReportRenderer() core.Renderer {
-	if f.billingReportRenderer == nil {
-		renderer := syncsign.NewBillingReportRenderer(f.conf, f.logger, f.newBillingReportTemplate(), f.newDataSource())
-		go renderer.ObserveDataSource(f.ctx)
-		f.billingReportRenderer = renderer
+// newIndoorClimateRenderer returns a renderer for indoor climate of passed widget. Rooms which define their own
+// template get it in addition to the default template of this widget. Header template is used for grouped rooms,
+// default header template is loaded if rooms are grouped without header template config.
+func (f *factory) newIndoorClimateRenderer(widget string) core.Renderer {
+	configKey := "hdb." + widget
+	templates := syncsign.IndoorClimateTemplates{
+		Room:  f.newTemplate(configKey + ".template"),
+		Rooms: make(map[string]core.Template),
+	}
+	for roomId, templateFile := range syncsign.RoomTemplateFiles(f.conf, configKey) {
+		templates.Rooms[roomId] = f.newTemplateFromFile(templateFile)
 	}
-	return f.billingReportRenderer
+	if templateFile := syncsign.GroupHeaderTemplateFile(f.conf, configKey); templateFile != "" {
+		templates.GroupHeader = f.newTemplateFromFile(templateFile)
+	}
+	renderer := syncsign.NewIndoorClimateRendererWithTemplates(f.conf, configKey, f.logger, templates, f.newDataSource())
+	go renderer.ObserveDataSource(f.ctx)
+	return renderer
+}
+
+func (f *factory) newBillingReportRenderer(widget string) core.Renderer {
+	configKey := "hdb." + widget
+	renderer := syncsign.NewBillingReportRendererWithConfigKey(f.conf, configKey, f.logger, f.newTemplate(configKey+".template"), f.newDataSource())
+	go renderer.ObserveDataSource(f.ctx)
+	return renderer
+}
+
+func (f *factory) newWeatherRenderer(widget string) core.Renderer {
+	configKey := "hdb." + widget
+	renderer := syncsign.NewWeatherRendererWithConfigKey(f.conf, configKey, f.logger, f.newTemplate(configKey+".template.current"), f.newTemplate(configKey+".template.forecast"), f.newDataSource())
+	go renderer.ObserveDataSource(f.ctx)
+	return renderer
 }
 
-func (f *factory) newWeatherRenderer() core.Renderer {
-	if f.weatherRenderer == nil {
-		renderer := syncsign.NewWeatherRenderer(f.conf, f.logger, f.newCurrentWeatherTemplate(), f.newForeCastWeatherTemplate(), f.newDataSource())
-		go renderer.ObserveDataSource(f.ctx)
-		f.weatherRenderer = renderer
+// newVentilationRenderer returns a renderer for ventilation advices which uses data observed by the indoor climate
+// and weather widgets defined by "indoorclimate" and "weather" of passed widget. Nil is returned if one of these
+// widgets doesn't provide such data. Both widgets are checked before one of them is created, so no data source
+// is started for an invalid ventilation widget.
+func (f *factory) newVentilationRenderer(widget string) core.Renderer {
+
+	configKey := "hdb." + widget
+	indoorClimateWidget, ok := f.providerWidget(widget, "indoorclimate", syncsign.WIDGET_INDOORCLIMATE)
+	if !ok {
+		return nil
 	}
-	return f.weatherRenderer
+	weatherWidget, ok := f.providerWidget(widget, "weather", syncsign.WIDGET_WEATHER)
+	if !ok {
+		return nil
+	}
+
+	indoorClimate, ok := f.newWidgetRenderer(indoorClimateWidget).(syncsign.IndoorClimateProvider)
+	if !ok {
+		f.logger.Errorf("Widget %s doesn't provide indoor climate for %s.", indoorClimateWidget, widget)
+		return nil
+	}
+	weather, ok := f.newWidgetRenderer(weatherWidget).(syncsign.WeatherProvider)
+	if !ok {
+		f.logger.Errorf("Widget %s doesn't provide weather for %s.", weatherWidget, widget)
+		return nil
+	}
+	return syncsign.NewVentilationRendererWithConfigKey(f.conf, configKey, f.logger, f.newTemplate(configKey+".template"), indoorClimate, weather)
 }
 
+// newLowBatteryRenderer returns a renderer for devices with low battery which uses battery levels observed by
+// the indoor climate widget defined by "indoorclimate" of passed widget. Nil is returned if this widget doesn't
+// provide battery levels.
+func (f *factory) newLowBatteryRenderer(widget string) core.Renderer {
+
+	configKey := "hdb." + widget
+	indoorClimateWidget, ok := f.providerWidget(widget, "indoorclimate", syncsign.WIDGET_INDOORCLIMATE)
+	if !ok {
+		return nil
+	}
+	batteries, ok := f.newWidgetRenderer(indoorClimateWidget).(syncsign.BatteryReporter)
+	if !ok {
+		f.logger.Errorf("Widget %s doesn't provide battery levels for %s.", indoorClimateWidget, widget)
+		return nil
+	}
+	return syncsign.NewLowBatteryRendererWithConfigKey(f.conf, configKey, f.logger, f.newTemplate(configKey+".template"), batteries)
+}
+
+// newComfortRenderer returns a renderer for the number of rooms outside of their comfort range, provided by
+// the indoor climate widget defined by "indoorclimate" of passed widget. Nil is returned if this widget doesn't
+// provide a comfort summary.
+func (f *factory) newComfortRenderer(widget string) core.Renderer {
+
+	configKey := "hdb." + widget
+	indoorClimateWidget, ok := f.providerWidget(widget, "indoorclimate", syncsign.WIDGET_INDOORCLIMATE)
+	if !ok {
+		return nil
+	}
+	comfort, ok := f.newWidgetRenderer(indoorClimateWidget).(syncsign.ComfortSummary)
+	if !ok {
+		f.logger.Errorf("Widget %s doesn't provide a comfort summary for %s.", indoorClimateWidget, widget)
+		return nil
+	}
+	return syncsign.NewComfortRendererWithConfigKey(f.conf, configKey, f.logger, f.newTemplate(configKey+".template"), comfort)
+}
+
+// providerWidget returns the widget defined by passed provider config key of a widget, defaults to the provider
+// type itself. False is returned if this widget isn't of expected provider type.
+func (f *factory) providerWidget(widget, providerConfigKey, providerType string) (string, bool) {
+	provider := f.conf.Get("hdb."+widget+"."+providerConfigKey, config.AsStringPtr(providerType))
+	if widgetType := syncsign.WidgetType(f.conf, *provider); widgetType != providerType {
+		f.logger.Errorf("Widget %s of type %s can't be used as %s provider for %s.", *provider, widgetType, providerType, widget)
+		return *provider, false
+	}
+	return *provider, true
+}
+
+// widgetRenderers returns a copy of all created widget renderers, by widget.
+func (f *factory) widgetRenderers() map[string]core.Renderer {
+
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	renderers := make(map[string]core.Renderer, len(f.widgetRenderer))
+	for widget, renderer := range f.widgetRenderer {
+		renderers[widget] = renderer
+	}
+	return renderers
+}
+
+// unmappedDevices returns unmapped devices of all widget renderers which are able to report them, by widget.
+// Reporters are queried without holding the lock, because they may have to fetch data from their data source.
+func (f *factory) unmappedDevices() map[string][]syncsign.UnmappedDevice {
+
+	devices := make(map[string][]syncsign.UnmappedDevice)
+	for widget, renderer := range f.widgetRenderers() {
+		if reporter, ok := renderer.(syncsign.DeviceReporter); ok {
+			devices[widget] = reporter.UnmappedDevices()
+		}
+	}
+	return devices
+}
+
+// offlineDevices returns offline devices of all widget renderers which are able to report them, by widget.
+// Reporters are queried without holding the lock, because they may have to fetch data from their data source.
+func (f *factory) offlineDevices() map[string][]syncsign.OfflineDevice {
+
+	devices := make(map[string][]syncsign.OfflineDevice)
+	for widget, renderer := range f.widgetRenderers() {
+		if reporter, ok := renderer.(syncsign.OfflineDeviceReporter); ok {
+			devices[widget] = reporter.OfflineDevices()
+		}
+	}
+	return devices
+}
+
+// newDataSource returns a new data source, which is observed until context of this factory is done.
+// Caller has to hold the lock.
 func (f *factory) newDataSource() core.DataSource {
 	dataSource := datasource.New(f.conf, f.logger)
 	f.wg.Add(1)
@@ -149,14 +322,14 @@ func (f *factory) indoorClimateDevices() []string {
 }
 
 func (f *factory) newDisplayConfig() *syncsign.DisplayConfig {
-	if f.displayConfig == nil {
-		f.displayConfig = syncsign.NewDisplayConfig(f.conf)
-	}
 	return f.displayConfig
 }
 
 func (f *factory) dataSourceMetrics() map[int][]metrics.Measurement {
 
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
 	dataSourceMetrics := make(map[int][]metrics.Measurement)
 	for id, datasource := range f.datasources {
 		dataSourceMetrics[id] = datasource.Metrics()
@@ -4,7 +4,11 @@ import (
 	"context"
 	"errors"
 	"github.com/stretchr/testify/suite"
+	config "github.com/tommzn/go-config"
+	syncsign "github.com/tommzn/hdb-renderer-syncsign"
+	"sync"
 	"testing"
+	"time"
 )
 
 type FactoryTestSuite struct {
@@ -21,16 +25,152 @@ func (suite *FactoryTestSuite) TestCreate() {
 
 	suite.NotNil(diFactory.newResponseRendererTemplate())
 	suite.NotNil(diFactory.newErrorRendererTemplate())
-	suite.NotNil(diFactory.newIndoorClimateTemplate())
+	suite.NotNil(diFactory.newTemplate("hdb.indoorclimate.template"))
 
 	suite.NotNil(diFactory.newErrorRenderer(errors.New("Error occured!")))
 	suite.NotNil(diFactory.newErrorResponseRenderer("Node01", errors.New("Error occured!")))
 	suite.NotNil(diFactory.newResponseRenderer("Node01"))
-	suite.NotNil(diFactory.newIndoorClimateRenderer())
+	suite.NotNil(diFactory.newIndoorClimateRenderer("indoorclimate"))
+	suite.NotNil(diFactory.templates["templates/indoorclimate_tile.json"])
+	suite.Equal(diFactory.newTemplateFromFile("indoorclimate_tile.json"), diFactory.templates["templates/indoorclimate_tile.json"])
+	suite.NotNil(diFactory.newIndoorClimateRenderer("groupedclimate"))
+	suite.NotNil(diFactory.templates["templates/indoorclimate_header.json"])
 
 	suite.NotNil(diFactory.newDataSource())
 
 	displayConfig := diFactory.newDisplayConfig()
 	suite.NotNil(displayConfig)
-	suite.Len(displayConfig.All(), 3)
+	suite.Len(displayConfig.All(), 4)
+}
+
+func (suite *FactoryTestSuite) TestCreateIndoorClimateRendererWithDefaultGroupHeader() {
+
+	diFactory := newFactory(loadConfigForTest(nil), loggerForTest(), context.Background())
+
+	suite.NotNil(diFactory.newIndoorClimateRenderer("defaultheaderclimate"))
+	suite.NotNil(diFactory.templates["templates/"+syncsign.DEFAULT_GROUP_HEADER_TEMPLATE])
+}
+
+func (suite *FactoryTestSuite) TestCreateWidgetRenderer() {
+
+	diFactory := newFactory(loadConfigForTest(nil), loggerForTest(), context.Background())
+
+	suite.NotNil(diFactory.newResponseRenderer("Display04"))
+	suite.Len(diFactory.widgetRenderer, 2)
+	suite.NotNil(diFactory.widgetRenderer["outdoor"])
+	suite.NotNil(diFactory.widgetRenderer["timestamp"])
+
+	suite.NotNil(diFactory.newResponseRenderer("Display01"))
+	suite.Len(diFactory.widgetRenderer, 5)
+
+	suite.Nil(diFactory.newWidgetRenderer("unknown"))
+}
+
+func (suite *FactoryTestSuite) TestCreateRendererConcurrently() {
+
+	diFactory := newFactory(loadConfigForTest(nil), loggerForTest(), context.Background())
+
+	wg := &sync.WaitGroup{}
+	for _, nodeId := range []string{"Display01", "Display02", "Display03", "Display04"} {
+		wg.Add(2)
+		go func(nodeId string) {
+			defer wg.Done()
+			suite.NotNil(diFactory.newResponseRenderer(nodeId))
+		}(nodeId)
+		go func(nodeId string) {
+			defer wg.Done()
+			suite.NotNil(diFactory.newErrorResponseRenderer(nodeId, errors.New("Error occured!")))
+		}(nodeId)
+	}
+	wg.Wait()
+	suite.Len(diFactory.responseRenderer, 4)
+}
+
+func (suite *FactoryTestSuite) TestReportDevicesWhileCreatingRenderer() {
+
+	diFactory := newFactory(loadConfigForTest(nil), loggerForTest(), context.Background())
+
+	wg := &sync.WaitGroup{}
+	for _, nodeId := range []string{"Display01", "Display02", "Display03", "Display04"} {
+		wg.Add(2)
+		go func(nodeId string) {
+			defer wg.Done()
+			diFactory.newResponseRenderer(nodeId)
+		}(nodeId)
+		go func() {
+			defer wg.Done()
+			suite.NotNil(diFactory.unmappedDevices())
+			suite.NotNil(diFactory.offlineDevices())
+		}()
+	}
+	wg.Wait()
+	suite.Len(diFactory.unmappedDevices(), 1)
+	suite.Len(diFactory.offlineDevices(), 1)
+}
+
+func (suite *FactoryTestSuite) TestCreateResponseRendererWithContentHash() {
+
+	conf := loadConfigForTest(config.AsStringPtr("fixtures/testconfig02.yml"))
+	dsMock := newDataSourceMock([]string{}, loggerForTest())
+	dsMock.(*dataSourceMock).initMessages()
+	diFactory := newFactory(conf, loggerForTest(), context.Background())
+	diFactory.widgetRenderer["billingreport"] = syncsign.NewBillingReportRenderer(conf, loggerForTest(), diFactory.newTemplate("hdb.billingreport.template"), dsMock)
+
+	renderer, ok := diFactory.newResponseRenderer("Display01").(*syncsign.ResponseRenderer)
+	suite.True(ok)
+	response1, err1 := renderer.Response()
+	suite.Nil(err1)
+	time.Sleep(1100 * time.Millisecond)
+	response2, err2 := renderer.Response()
+	suite.Nil(err2)
+	suite.Equal(response1.Data[0].RenderId, response2.Data[0].RenderId)
+}
+
+func (suite *FactoryTestSuite) TestCreateVentilationRenderer() {
+
+	diFactory := newFactory(loadConfigForTest(nil), loggerForTest(), context.Background())
+
+	suite.NotNil(diFactory.newWidgetRenderer("ventilation"))
+	suite.Len(diFactory.widgetRenderer, 3)
+	suite.NotNil(diFactory.widgetRenderer["indoorclimate"])
+	suite.NotNil(diFactory.widgetRenderer["outdoor"])
+
+	suite.Nil(diFactory.newWidgetRenderer("brokenventilation"))
+	suite.Nil(diFactory.newWidgetRenderer("selfventilation"))
+	suite.Nil(diFactory.newWidgetRenderer("loopventilation"))
+	suite.Nil(diFactory.newWidgetRenderer("loopventilation2"))
+	suite.Len(diFactory.pendingWidgets, 0)
+}
+
+func (suite *FactoryTestSuite) TestCreateInvalidVentilationRenderer() {
+
+	diFactory := newFactory(loadConfigForTest(nil), loggerForTest(), context.Background())
+
+	suite.Nil(diFactory.newWidgetRenderer("brokenweatherventilation"))
+	suite.Nil(diFactory.widgetRenderer["indoorclimate"])
+	suite.Len(diFactory.datasources, 0)
+}
+
+func (suite *FactoryTestSuite) TestCreateComfortRenderer() {
+
+	diFactory := newFactory(loadConfigForTest(nil), loggerForTest(), context.Background())
+
+	suite.NotNil(diFactory.newWidgetRenderer("comfort"))
+	suite.Len(diFactory.widgetRenderer, 2)
+	suite.NotNil(diFactory.widgetRenderer["indoorclimate"])
+
+	suite.Nil(diFactory.newWidgetRenderer("brokencomfort"))
+}
+
+func (suite *FactoryTestSuite) TestCreateLowBatteryRenderer() {
+
+	diFactory := newFactory(loadConfigForTest(nil), loggerForTest(), context.Background())
+
+	suite.NotNil(diFactory.newWidgetRenderer("lowbattery"))
+	suite.Len(diFactory.widgetRenderer, 2)
+	suite.NotNil(diFactory.widgetRenderer["indoorclimate"])
+
+	suite.Nil(diFactory.newWidgetRenderer("looplowbattery"))
+	suite.Nil(diFactory.newWidgetRenderer("loopventilation3"))
+	suite.Len(diFactory.pendingWidgets, 0)
 }
@@ -15,5 +15,8 @@ require (
 	github.com/tommzn/hdb-message-client v1.2.0
 	github.com/tommzn/hdb-renderer-core v1.1.6
 	github.com/tommzn/hdb-renderer-syncsign v1.2.23
+	golang.org/x/image v0.0.0-20220302094943-723b81ca9867 // indirect
 	google.golang.org/protobuf v1.27.1
 )
+
+replace github.com/tommzn/hdb-renderer-syncsign => ../
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"sort"
+	"time"
+)
+
+// newRenderStore returns a store which keeps passed number of renders at max.
+func newRenderStore(limit int) *renderStore {
+	if limit < 1 {
+		limit = 1
+	}
+	return &renderStore{
+		limit:   limit,
+		renders: make(map[string]*issuedRender),
+		order:   []string{},
+		nodes:   make(map[string]*nodeRenderStatus),
+	}
+}
+
+// Add saves passed render content and marks it as latest fetched render for given node.
+// If store limit is reached oldest render will be removed. A render which is issued again,
+// e.g. because content hasn't changed, becomes the latest one.
+func (store *renderStore) add(renderId, nodeId, content string) {
+
+	store.Lock()
+	defer store.Unlock()
+
+	now := time.Now()
+	if _, ok := store.renders[renderId]; ok {
+		store.removeFromOrder(renderId)
+	}
+	store.order = append(store.order, renderId)
+	store.renders[renderId] = &issuedRender{
+		RenderId: renderId,
+		NodeId:   nodeId,
+		Issued:   now,
+		content:  content,
+	}
+	for len(store.order) > store.limit {
+		delete(store.renders, store.order[0])
+		store.order = store.order[1:]
+	}
+	store.nodeStatus(nodeId).LastFetched = &renderInfo{RenderId: renderId, Timestamp: now}
+}
+
+// Confirm records acknowledgement of passed render and returns it's content.
+// False is returned if there's no such render.
+func (store *renderStore) confirm(renderId string) (string, bool) {
+
+	store.Lock()
+	defer store.Unlock()
+
+	render, ok := store.renders[renderId]
+	if !ok {
+		return "", false
+	}
+	now := time.Now()
+	render.Confirmed = &now
+	store.nodeStatus(render.NodeId).LastConfirmed = &renderInfo{RenderId: renderId, Timestamp: now}
+	return render.content, true
+}
+
+// NodeStatusList returns latest fetched and confirmed render for all nodes, sorted by node id.
+func (store *renderStore) nodeStatusList() []nodeRenderStatus {
+
+	store.RLock()
+	defer store.RUnlock()
+
+	statusList := []nodeRenderStatus{}
+	for _, status := range store.nodes {
+		statusList = append(statusList, *status)
+	}
+	sort.Slice(statusList, func(i, j int) bool {
+		return statusList[i].NodeId < statusList[j].NodeId
+	})
+	return statusList
+}
+
+// nodeStatus returns render status of passed node, a new one will be created if it doesn't exist.
+// Caller has to hold the lock.
+func (store *renderStore) nodeStatus(nodeId string) *nodeRenderStatus {
+	if _, ok := store.nodes[nodeId]; !ok {
+		store.nodes[nodeId] = &nodeRenderStatus{NodeId: nodeId}
+	}
+	return store.nodes[nodeId]
+}
+
+// removeFromOrder removes passed render id from list of issued renders.
+// Caller has to hold the lock.
+func (store *renderStore) removeFromOrder(renderId string) {
+	for idx, id := range store.order {
+		if id == renderId {
+			store.order = append(store.order[:idx], store.order[idx+1:]...)
+			return
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+type RenderStoreTestSuite struct {
+	suite.Suite
+}
+
+func TestRenderStoreTestSuite(t *testing.T) {
+	suite.Run(t, new(RenderStoreTestSuite))
+}
+
+func (suite *RenderStoreTestSuite) TestAddAndConfirm() {
+
+	store := newRenderStore(10)
+	store.add("Render01", "Node01", "Content01")
+
+	render, ok := store.renders["Render01"]
+	suite.True(ok)
+	suite.Equal("Node01", render.NodeId)
+	suite.Nil(render.Confirmed)
+
+	statusList := store.nodeStatusList()
+	suite.Len(statusList, 1)
+	suite.Equal("Render01", statusList[0].LastFetched.RenderId)
+	suite.Nil(statusList[0].LastConfirmed)
+
+	content, ok := store.confirm("Render01")
+	suite.True(ok)
+	suite.Equal("Content01", content)
+
+	suite.NotNil(store.renders["Render01"].Confirmed)
+	statusList = store.nodeStatusList()
+	suite.Equal("Render01", statusList[0].LastConfirmed.RenderId)
+
+	_, ok = store.confirm("Render99")
+	suite.False(ok)
+}
+
+func (suite *RenderStoreTestSuite) TestHistoryLimit() {
+
+	store := newRenderStore(2)
+	store.add("Render01", "Node01", "Content01")
+	store.add("Render02", "Node02", "Content02")
+	store.add("Render03", "Node01", "Content03")
+
+	_, ok := store.renders["Render01"]
+	suite.False(ok)
+	_, ok = store.renders["Render02"]
+	suite.True(ok)
+	_, ok = store.renders["Render03"]
+	suite.True(ok)
+	suite.Len(store.renders, 2)
+
+	statusList := store.nodeStatusList()
+	suite.Len(statusList, 2)
+	suite.Equal("Node01", statusList[0].NodeId)
+	suite.Equal("Render03", statusList[0].LastFetched.RenderId)
+}
+
+func (suite *RenderStoreTestSuite) TestReissueRender() {
+
+	store := newRenderStore(2)
+	store.add("Render01", "Node01", "Content01")
+	store.add("Render02", "Node02", "Content02")
+	store.add("Render01", "Node01", "Content01")
+	store.add("Render03", "Node03", "Content03")
+
+	_, ok := store.renders["Render01"]
+	suite.True(ok)
+	_, ok = store.renders["Render02"]
+	suite.False(ok)
+	suite.Equal([]string{"Render01", "Render03"}, store.order)
+}
@@ -15,17 +15,21 @@ import (
 
 	config "github.com/tommzn/go-config"
 	log "github.com/tommzn/go-log"
+	syncsign "github.com/tommzn/hdb-renderer-syncsign"
+	"github.com/tommzn/hdb-renderer-syncsign/preview"
 )
 
 func newServer(conf config.Config, logger log.Logger, diFactory *factory) *webServer {
 	port := conf.Get("hdb.server.port", config.AsStringPtr("8080"))
 	minify := conf.GetAsBool("hdb.server.minify", config.AsBoolPtr(true))
+	renderHistory := conf.GetAsInt("hdb.server.render_history", config.AsIntPtr(100))
 	return &webServer{
 		port:           *port,
 		minifyResponse: *minify,
 		conf:           conf,
 		logger:         logger,
 		diFactory:      diFactory,
+		renders:        newRenderStore(*renderHistory),
 	}
 }
 
@@ -41,6 +45,10 @@ func (server *webServer) Run(ctx context.Context, waitGroup *sync.WaitGroup) err
 
 	router.HandleFunc("/renders/nodes/{nodeid}", server.handleNodeRequest).Methods("GET")
 	router.HandleFunc("/renders/{renderid}", server.handleRenderRequest).Methods("GET")
+	router.HandleFunc("/nodes", server.handleNodeStatusRequest).Methods("GET")
+	router.HandleFunc("/devices/unmapped", server.handleUnmappedDevicesRequest).Methods("GET")
+	router.HandleFunc("/devices/offline", server.handleOfflineDevicesRequest).Methods("GET")
+	router.HandleFunc("/preview/nodes/{nodeid}", server.handlePreviewRequest).Methods("GET")
 
 	router.HandleFunc("/health", server.handleHealthCheckRequest).Methods("GET")
 	router.HandleFunc("/metrics", server.handleMetricsRequest).Methods("GET")
@@ -94,7 +102,9 @@ func (server *webServer) handleHealthCheckRequest(w http.ResponseWriter, r *http
 	w.WriteHeader(http.StatusNoContent)
 }
 
-// HandleRenderRequest handles requests for render id, which will be answered with a 204 status code by default.
+// HandleRenderRequest handles requests for render id. Stored content of a render is returned and
+// the request is recorded as acknowledgement by the display. Requests for unknown render ids
+// will be answered with a 204 status code.
 func (server *webServer) handleRenderRequest(w http.ResponseWriter, r *http.Request) {
 
 	defer server.logger.Flush()
@@ -102,6 +112,10 @@ func (server *webServer) handleRenderRequest(w http.ResponseWriter, r *http.Requ
 	vars := mux.Vars(r)
 	if renderId, ok := vars["renderid"]; ok {
 		server.logger.Infof("Receive request for render id: %s", renderId)
+		if content, ok := server.renders.confirm(renderId); ok {
+			server.writeResponse(w, content)
+			return
+		}
 		res := emptyResponse{
 			StatusCode: http.StatusNoContent,
 		}
@@ -136,9 +150,69 @@ func (server *webServer) handleNodeRequest(w http.ResponseWriter, r *http.Reques
 		server.writeResponseError(w, nodeId, err)
 		return
 	}
+	server.storeRender(nodeId, content)
 	server.writeResponse(w, content)
 }
 
+// HandleNodeStatusRequest returns latest fetched and confirmed render for each node.
+func (server *webServer) handleNodeStatusRequest(w http.ResponseWriter, r *http.Request) {
+	json.NewEncoder(w).Encode(server.renders.nodeStatusList())
+}
+
+// HandleUnmappedDevicesRequest returns all devices which send data but aren't assigned by config, for each widget.
+func (server *webServer) handleUnmappedDevicesRequest(w http.ResponseWriter, r *http.Request) {
+	json.NewEncoder(w).Encode(server.diFactory.unmappedDevices())
+}
+
+// HandleOfflineDevicesRequest returns all devices which haven't send data for a while, for each widget.
+func (server *webServer) handleOfflineDevicesRequest(w http.ResponseWriter, r *http.Request) {
+	json.NewEncoder(w).Encode(server.diFactory.offlineDevices())
+}
+
+// HandlePreviewRequest renders content for passed node as image. Default format is SVG, use
+// query parameter "format=png" to get a PNG image. Errors are rendered as a display would show them.
+func (server *webServer) handlePreviewRequest(w http.ResponseWriter, r *http.Request) {
+
+	defer server.logger.Flush()
+
+	vars := mux.Vars(r)
+	nodeId := vars["nodeid"]
+
+	var content string
+	var err error
+	if server.diFactory.newDisplayConfig().Exists(nodeId) {
+		content, err = server.diFactory.newResponseRenderer(nodeId).Content()
+	} else {
+		err = fmt.Errorf("Preview request for unknown node %s received.", nodeId)
+	}
+	if err != nil {
+		server.logger.Error(err)
+		content, _ = server.diFactory.newErrorResponseRenderer(nodeId, err).Content()
+	}
+
+	response, err := syncsign.ParseResponse(content)
+	if err != nil {
+		server.logger.Error("Unable to parse content for preview, reason: ", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	image, contentType := []byte{}, "image/svg+xml"
+	if r.URL.Query().Get("format") == "png" {
+		image, err = preview.New().PNG(response)
+		contentType = "image/png"
+	} else {
+		image, err = preview.New().SVG(response)
+	}
+	if err != nil {
+		server.logger.Error("Unable to generate preview, reason: ", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Write(image)
+}
+
 // handleMetricsRequest will collect metrics from all datasources.
 func (server *webServer) handleMetricsRequest(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(server.diFactory.dataSourceMetrics())
@@ -167,5 +241,21 @@ func (server *webServer) writeResponseError(w http.ResponseWriter, nodeId string
 	server.logger.Error(err)
 	errorRenderer := server.diFactory.newErrorResponseRenderer(nodeId, err)
 	errContent, _ := errorRenderer.Content()
+	server.storeRender(nodeId, errContent)
 	server.writeResponse(w, errContent)
 }
+
+// StoreRender saves passed content in render history, using render id of the content.
+func (server *webServer) storeRender(nodeId, content string) {
+
+	response, err := syncsign.ParseResponse(content)
+	if err != nil {
+		server.logger.Errorf("Unable to get render id from content for node %s, reason: %s", nodeId, err)
+		return
+	}
+	if len(response.Data) == 0 {
+		server.logger.Errorf("Unable to get render id from content for node %s, response contains no data.", nodeId)
+		return
+	}
+	server.renders.add(response.Data[0].RenderId, nodeId, content)
+}
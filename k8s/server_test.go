@@ -1,14 +1,17 @@
 package main
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"github.com/stretchr/testify/suite"
 	config "github.com/tommzn/go-config"
 	log "github.com/tommzn/go-log"
 	syncsign "github.com/tommzn/hdb-renderer-syncsign"
+	"image/png"
 	"io/ioutil"
 	"net/http"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -69,6 +72,37 @@ func (suite *ServerTestSuite) TestRenderRequest() {
 	suite.stopServer()
 }
 
+func (suite *ServerTestSuite) TestConfirmRender() {
+
+	server := suite.serverForTest()
+	suite.startServer(server)
+
+	resp1, err1 := http.Get("http://localhost:8080/renders/nodes/" + suite.nodeId)
+	suite.Nil(err1)
+	resData1 := suite.asTestResponse(suite.readBody(resp1))
+	suite.Len(resData1.Data, 1)
+	renderId := resData1.Data[0].RenderId
+
+	resp2, err2 := http.Get("http://localhost:8080/renders/" + renderId)
+	suite.Nil(err2)
+	suite.Equal(http.StatusOK, resp2.StatusCode)
+	resData2 := suite.asTestResponse(suite.readBody(resp2))
+	suite.Len(resData2.Data, 1)
+	suite.Equal(renderId, resData2.Data[0].RenderId)
+	suite.Equal(suite.nodeId, resData2.Data[0].NodeId)
+
+	resp3, err3 := http.Get("http://localhost:8080/nodes")
+	suite.Nil(err3)
+	suite.Equal(http.StatusOK, resp3.StatusCode)
+	statusList := []nodeRenderStatus{}
+	suite.Nil(json.Unmarshal(suite.readBody(resp3), &statusList))
+	suite.Len(statusList, 1)
+	suite.Equal(renderId, statusList[0].LastFetched.RenderId)
+	suite.Equal(renderId, statusList[0].LastConfirmed.RenderId)
+
+	suite.stopServer()
+}
+
 func (suite *ServerTestSuite) TestNodeRequest() {
 
 	server := suite.serverForTest()
@@ -93,6 +127,74 @@ func (suite *ServerTestSuite) TestNodeRequest() {
 	suite.stopServer()
 }
 
+func (suite *ServerTestSuite) TestPreviewRequest() {
+
+	server := suite.serverForTest()
+	suite.startServer(server)
+
+	resp1, err1 := http.Get("http://localhost:8080/preview/nodes/" + suite.nodeId)
+	suite.Nil(err1)
+	suite.Equal(http.StatusOK, resp1.StatusCode)
+	suite.Equal("image/svg+xml", resp1.Header.Get("Content-Type"))
+	suite.True(strings.HasPrefix(string(suite.readBody(resp1)), "<svg"))
+
+	resp2, err2 := http.Get("http://localhost:8080/preview/nodes/" + suite.nodeId + "?format=png")
+	suite.Nil(err2)
+	suite.Equal(http.StatusOK, resp2.StatusCode)
+	suite.Equal("image/png", resp2.Header.Get("Content-Type"))
+	_, err := png.Decode(bytes.NewReader(suite.readBody(resp2)))
+	suite.Nil(err)
+
+	resp3, err3 := http.Get("http://localhost:8080/preview/nodes/InvalidNodeId")
+	suite.Nil(err3)
+	suite.Equal(http.StatusOK, resp3.StatusCode)
+	suite.True(strings.Contains(string(suite.readBody(resp3)), "An error has occurred!"))
+
+	suite.stopServer()
+}
+
+func (suite *ServerTestSuite) TestUnmappedDevicesRequest() {
+
+	server := suite.serverForTest()
+	suite.startServer(server)
+
+	resp1, err1 := http.Get("http://localhost:8080/renders/nodes/" + suite.nodeId)
+	suite.Nil(err1)
+	suite.Equal(http.StatusOK, resp1.StatusCode)
+
+	resp2, err2 := http.Get("http://localhost:8080/devices/unmapped")
+	suite.Nil(err2)
+	suite.Equal(http.StatusOK, resp2.StatusCode)
+	devices := make(map[string][]syncsign.UnmappedDevice)
+	suite.Nil(json.Unmarshal(suite.readBody(resp2), &devices))
+	suite.Len(devices, 1)
+	suite.Len(devices["indoorclimate"], 1)
+	suite.Equal(suite.nodeId, devices["indoorclimate"][0].DeviceId)
+	suite.True(len(devices["indoorclimate"][0].Values) > 0)
+
+	suite.stopServer()
+}
+
+func (suite *ServerTestSuite) TestOfflineDevicesRequest() {
+
+	server := suite.serverForTest()
+	suite.startServer(server)
+
+	resp1, err1 := http.Get("http://localhost:8080/renders/nodes/" + suite.nodeId)
+	suite.Nil(err1)
+	suite.Equal(http.StatusOK, resp1.StatusCode)
+
+	resp2, err2 := http.Get("http://localhost:8080/devices/offline")
+	suite.Nil(err2)
+	suite.Equal(http.StatusOK, resp2.StatusCode)
+	devices := make(map[string][]syncsign.OfflineDevice)
+	suite.Nil(json.Unmarshal(suite.readBody(resp2), &devices))
+	suite.Len(devices, 1)
+	suite.Len(devices["indoorclimate"], 0)
+
+	suite.stopServer()
+}
+
 func (suite *ServerTestSuite) startServer(server *webServer) {
 	suite.wg = &sync.WaitGroup{}
 	go func() {
@@ -108,9 +210,9 @@ func (suite *ServerTestSuite) serverForTest() *webServer {
 	dsMock.(*dataSourceMock).initMessages()
 
 	diFactory := newFactory(suite.conf, suite.logger, suite.ctx)
-	diFactory.weatherRenderer = syncsign.NewWeatherRenderer(suite.conf, suite.logger, diFactory.newCurrentWeatherTemplate(), diFactory.newForeCastWeatherTemplate(), dsMock)
-	diFactory.indoorClimateRenderer = syncsign.NewIndoorClimateRenderer(suite.conf, suite.logger, diFactory.newIndoorClimateTemplate(), dsMock)
-	diFactory.billingReportRenderer = syncsign.NewBillingReportRenderer(suite.conf, suite.logger, diFactory.newBillingReportTemplate(), dsMock)
+	diFactory.widgetRenderer["weather"] = syncsign.NewWeatherRenderer(suite.conf, suite.logger, diFactory.newTemplate("hdb.weather.template.current"), diFactory.newTemplate("hdb.weather.template.forecast"), dsMock)
+	diFactory.widgetRenderer["indoorclimate"] = syncsign.NewIndoorClimateRenderer(suite.conf, suite.logger, diFactory.newTemplate("hdb.indoorclimate.template"), dsMock)
+	diFactory.widgetRenderer["billingreport"] = syncsign.NewBillingReportRenderer(suite.conf, suite.logger, diFactory.newTemplate("hdb.billingreport.template"), dsMock)
 
 	return newServer(suite.conf, suite.logger, diFactory)
 }
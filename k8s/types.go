@@ -4,6 +4,7 @@ import (
 	"context"
 	"net/http"
 	"sync"
+	"time"
 
 	config "github.com/tommzn/go-config"
 	log "github.com/tommzn/go-log"
@@ -19,26 +20,53 @@ type webServer struct {
 	logger         log.Logger
 	diFactory      *factory
 	httpServer     *http.Server
+	renders        *renderStore
 }
 
 type factory struct {
-	conf                    config.Config
-	logger                  log.Logger
-	ctx                     context.Context
-	wg                      *sync.WaitGroup
-	errorTemplate           core.Template
-	responseTemplate        core.Template
-	indoorClimateTemplate   core.Template
-	billingReportTemplate   core.Template
-	currentWeatherTemplate  core.Template
-	forecastWeatherTemplate core.Template
-	timestampTemplate       core.Template
-	indoorClimateRenderer   core.Renderer
-	billingReportRenderer   core.Renderer
-	weatherRenderer         core.Renderer
-	responseRenderer        map[string]core.Renderer
-	displayConfig           *syncsign.DisplayConfig
-	datasources             []datasource.Client
+	conf              config.Config
+	logger            log.Logger
+	ctx               context.Context
+	wg                *sync.WaitGroup
+	errorTemplate     core.Template
+	responseTemplate  core.Template
+	timestampTemplate core.Template
+	templates         map[string]core.Template
+	widgetRenderer    map[string]core.Renderer
+	pendingWidgets    map[string]bool
+	responseRenderer  map[string]core.Renderer
+	displayConfig     *syncsign.DisplayConfig
+	datasources       []datasource.Client
+	lock              sync.Mutex
+	templateLock      sync.Mutex
+}
+
+// renderStore keeps a limited number of issued renders and the latest render status for each node.
+type renderStore struct {
+	sync.RWMutex
+	limit   int
+	renders map[string]*issuedRender
+	order   []string
+	nodes   map[string]*nodeRenderStatus
+}
+
+type issuedRender struct {
+	RenderId  string     `json:"renderId"`
+	NodeId    string     `json:"nodeId"`
+	Issued    time.Time  `json:"issued"`
+	Confirmed *time.Time `json:"confirmed,omitempty"`
+	content   string
+}
+
+type nodeRenderStatus struct {
+	NodeId        string      `json:"nodeId"`
+	LastFetched   *renderInfo `json:"lastFetched,omitempty"`
+	LastConfirmed *renderInfo `json:"lastConfirmed,omitempty"`
+}
+
+type renderInfo struct {
+	RenderId  string    `json:"renderId"`
+	Timestamp time.Time `json:"timestamp"`
 }
 
 type emptyResponse struct {
@@ -0,0 +1,75 @@
+package syncsign
+
+import (
+	"fmt"
+	"sort"
+
+	config "github.com/tommzn/go-config"
+	log "github.com/tommzn/go-log"
+	core "github.com/tommzn/hdb-renderer-core"
+)
+
+// NewLowBatteryRenderer returns a renderer which lists all devices with low battery, using battery levels of passed reporter.
+func NewLowBatteryRenderer(conf config.Config, logger log.Logger, template core.Template, batteries BatteryReporter) *LowBatteryRenderer {
+	return NewLowBatteryRendererWithConfigKey(conf, "hdb.lowbattery", logger, template, batteries)
+}
+
+// NewLowBatteryRendererWithConfigKey returns a renderer for devices with low battery which uses settings from passed config key, e.g. "hdb.lowbattery".
+func NewLowBatteryRendererWithConfigKey(conf config.Config, configKey string, logger log.Logger, template core.Template, batteries BatteryReporter) *LowBatteryRenderer {
+	return &LowBatteryRenderer{
+		template:  template,
+		anchor:    anchorFromConfig(conf, configKey+".anchor"),
+		size:      sizeFromConfig(conf, configKey+".size"),
+		logger:    logger,
+		batteries: batteries,
+		threshold: floatFromConfig(conf, configKey+".threshold", 20),
+		limit:     forcePositive(*conf.GetAsInt(configKey+".limit", config.AsIntPtr(0))),
+	}
+}
+
+// Content generates an element for each device with a battery level below threshold, worst first.
+// Devices are placed from top to bottom, nothing is generated if all batteries are fine.
+func (renderer *LowBatteryRenderer) Content() (string, error) {
+
+	defer renderer.logger.Flush()
+
+	content := ""
+	anchor := renderer.anchor
+	for idx, battery := range renderer.lowBatteries() {
+		elementContent, err := renderer.template.RenderWith(lowBatteryData{
+			Anchor:       anchor,
+			DisplayIndex: idx,
+			DeviceId:     battery.DeviceId,
+			RoomName:     battery.RoomName,
+			Percent:      fmt.Sprintf("%.0f", battery.Percent),
+		})
+		if err != nil {
+			return content, err
+		}
+		content += elementContent
+		anchor.Y += renderer.size.Height
+	}
+	return content, nil
+}
+
+// lowBatteries returns all devices with a battery level below threshold, sorted by battery level and device id.
+// Number of devices is limited if a limit has been defined.
+func (renderer *LowBatteryRenderer) lowBatteries() []DeviceBattery {
+
+	batteries := []DeviceBattery{}
+	for _, battery := range renderer.batteries.DeviceBatteries() {
+		if battery.Percent < renderer.threshold {
+			batteries = append(batteries, battery)
+		}
+	}
+	sort.Slice(batteries, func(i, j int) bool {
+		if batteries[i].Percent != batteries[j].Percent {
+			return batteries[i].Percent < batteries[j].Percent
+		}
+		return batteries[i].DeviceId < batteries[j].DeviceId
+	})
+	if renderer.limit > 0 && len(batteries) > renderer.limit {
+		return batteries[:renderer.limit]
+	}
+	return batteries
+}
@@ -0,0 +1,63 @@
+package syncsign
+
+import (
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+type LowBatteryTestSuite struct {
+	suite.Suite
+}
+
+func TestLowBatteryTestSuite(t *testing.T) {
+	suite.Run(t, new(LowBatteryTestSuite))
+}
+
+func (suite *LowBatteryTestSuite) TestGenerateContent() {
+
+	renderer := lowBatteryRendererForTest(indoorClimateRendererForTest("fixtures/testconfig02.yml"))
+	suite.Equal(50.0, renderer.threshold)
+	suite.Equal(2, renderer.limit)
+
+	items, err := itemsFromRenderer(renderer)
+	suite.Nil(err)
+	suite.Len(items, 1)
+	textData := items[0].Data.(*TextData)
+	suite.Equal("Room1 DEVICE2 23%", textData.Text)
+	suite.Equal(Block{X: 500, Y: 400, W: 300, H: 20}, textData.Block)
+}
+
+func (suite *LowBatteryTestSuite) TestSortAndLimit() {
+
+	renderer := lowBatteryRendererForTest(newBatteryReporterMock([]DeviceBattery{
+		DeviceBattery{DeviceId: "Device1", RoomName: "Room1", Percent: 40},
+		DeviceBattery{DeviceId: "Device2", RoomName: "Room2", Percent: 5},
+		DeviceBattery{DeviceId: "Device3", RoomName: "Room3", Percent: 80},
+		DeviceBattery{DeviceId: "Device0", RoomName: "Room1", Percent: 40},
+	}))
+
+	batteries := renderer.lowBatteries()
+	suite.Len(batteries, 2)
+	suite.Equal("Device2", batteries[0].DeviceId)
+	suite.Equal("Device0", batteries[1].DeviceId)
+
+	items, err := itemsFromRenderer(renderer)
+	suite.Nil(err)
+	suite.Len(items, 2)
+	suite.Equal(420, items[1].Data.(*TextData).Block.Y)
+}
+
+func (suite *LowBatteryTestSuite) TestAllBatteriesFine() {
+
+	renderer := lowBatteryRendererForTest(newBatteryReporterMock([]DeviceBattery{
+		DeviceBattery{DeviceId: "Device1", RoomName: "Room1", Percent: 80},
+	}))
+
+	content, err := renderer.Content()
+	suite.Nil(err)
+	suite.Equal("", content)
+
+	items, err := itemsFromRenderer(renderer)
+	suite.Nil(err)
+	suite.Len(items, 0)
+}
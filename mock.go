@@ -3,9 +3,11 @@ package syncsign
 import (
 	"context"
 	"errors"
+	"sync"
 
 	"github.com/golang/protobuf/proto"
 	hdbcore "github.com/tommzn/hdb-core"
+	events "github.com/tommzn/hdb-events-go"
 	core "github.com/tommzn/hdb-renderer-core"
 )
 
@@ -14,6 +16,7 @@ type datasourceMock struct {
 	shouldReturnEmpty bool
 	data              map[hdbcore.DataSource][]proto.Message
 	eventChan         chan proto.Message
+	lock              sync.Mutex
 }
 
 func newDataSourceMock(shouldReturnError, shouldReturnEmpty bool, data map[hdbcore.DataSource][]proto.Message) core.DataSource {
@@ -25,7 +28,7 @@ func newDataSourceMock(shouldReturnError, shouldReturnEmpty bool, data map[hdbco
 		shouldReturnError: shouldReturnError,
 		shouldReturnEmpty: shouldReturnError,
 		data:              data,
-		eventChan:         make(chan proto.Message, chanLen+10),
+		eventChan:         make(chan proto.Message, 2*chanLen+10),
 	}
 }
 
@@ -51,6 +54,9 @@ func (mock *datasourceMock) All(datasource hdbcore.DataSource) ([]proto.Message,
 
 func (mock *datasourceMock) Observe(filter *[]hdbcore.DataSource) <-chan proto.Message {
 
+	mock.lock.Lock()
+	defer mock.lock.Unlock()
+
 	if filter != nil {
 		for _, datasource := range *filter {
 			for _, message := range mock.data[datasource] {
@@ -67,9 +73,17 @@ func (mock *datasourceMock) Observe(filter *[]hdbcore.DataSource) <-chan proto.M
 }
 
 func (mock *datasourceMock) writeToMessageChannel(message proto.Message) {
+	mock.lock.Lock()
+	defer mock.lock.Unlock()
 	mock.eventChan <- message
 }
 
+func (mock *datasourceMock) closeMessageChannel() {
+	mock.lock.Lock()
+	defer mock.lock.Unlock()
+	close(mock.eventChan)
+}
+
 type failingTemplate struct {
 }
 
@@ -106,10 +120,46 @@ func (renderer *rendererMock) Content() (string, error) {
 	if renderer.shouldReturnEmptyContent {
 		return "", nil
 	} else {
-		return "{\"id\": \"Item1\"},{\"id\": \"Item2\"},", nil
+		return "{\"type\": \"TEXT\", \"data\": {\"text\": \"Item1\", \"id\": \"Item1\"}},{\"type\": \"TEXT\", \"data\": {\"text\": \"Item2\", \"id\": \"Item2\"}},", nil
 	}
 }
 
 func (renderer *rendererMock) ObserveDataSource(ctx context.Context) {
 
 }
+
+type weatherProviderMock struct {
+	currentWeather *events.CurrentWeather
+}
+
+func newWeatherProviderMock(currentWeather *events.CurrentWeather) WeatherProvider {
+	return &weatherProviderMock{currentWeather: currentWeather}
+}
+
+func (mock *weatherProviderMock) CurrentWeather() *events.CurrentWeather {
+	return mock.currentWeather
+}
+
+type batteryReporterMock struct {
+	batteries []DeviceBattery
+}
+
+func newBatteryReporterMock(batteries []DeviceBattery) BatteryReporter {
+	return &batteryReporterMock{batteries: batteries}
+}
+
+func (mock *batteryReporterMock) DeviceBatteries() []DeviceBattery {
+	return mock.batteries
+}
+
+type comfortSummaryMock struct {
+	roomsOutOfRange int
+}
+
+func newComfortSummaryMock(roomsOutOfRange int) ComfortSummary {
+	return &comfortSummaryMock{roomsOutOfRange: roomsOutOfRange}
+}
+
+func (mock *comfortSummaryMock) RoomsOutOfRange() int {
+	return mock.roomsOutOfRange
+}
@@ -0,0 +1,105 @@
+package syncsign
+
+import (
+	"fmt"
+	"time"
+
+	config "github.com/tommzn/go-config"
+	events "github.com/tommzn/hdb-events-go"
+)
+
+// moldConfigFromConfig reads settings to calculate mold risk. Defaults to a humidity threshold of 70% at 5°C or above,
+// within last 7 days. Mold risk level is watch for 12 and act for 48 hours above threshold.
+func moldConfigFromConfig(conf config.Config, moldConfigKey string) moldConfig {
+	return moldConfig{
+		threshold:      floatFromConfig(conf, moldConfigKey+".threshold", 70),
+		minTemperature: floatFromConfig(conf, moldConfigKey+".min_temperature", 5),
+		days:           forcePositive(*conf.GetAsInt(moldConfigKey+".days", config.AsIntPtr(7))),
+		watchHours:     forcePositive(*conf.GetAsInt(moldConfigKey+".watch", config.AsIntPtr(12))),
+		actHours:       forcePositive(*conf.GetAsInt(moldConfigKey+".act", config.AsIntPtr(48))),
+	}
+}
+
+// addToMoldHistory adds passed humidity of a room to it's mold history. Time since previous humidity is counted
+// as above threshold if previous humidity has been above threshold. Humidity is above threshold only if room
+// temperature is at or above min temperature. Caller has to hold the lock.
+func (renderer *IndoorClimateRenderer) addToMoldHistory(roomId string, humidity reading, temperature *float64) {
+
+	history := renderer.moldHistory[roomId]
+	if humidity.timestamp.Before(history.lastTimestamp) {
+		return
+	}
+	if history.above {
+		history.periods = appendPeriod(history.periods, period{start: history.lastTimestamp, end: humidity.timestamp})
+	}
+	history.above = humidity.value > renderer.mold.threshold && (temperature == nil || *temperature >= renderer.mold.minTemperature)
+	history.lastTimestamp = humidity.timestamp
+
+	windowStart := humidity.timestamp.Add(-1 * renderer.moldWindow())
+	periods := []period{}
+	for _, moldPeriod := range history.periods {
+		if moldPeriod.end.After(windowStart) {
+			periods = append(periods, moldPeriod)
+		}
+	}
+	history.periods = periods
+	renderer.moldHistory[roomId] = history
+}
+
+// applyMoldRisk assigns hours above mold humidity threshold within mold window and resulting level to
+// passed room climate. If humidity is above threshold, time since latest humidity is counted up to it's max age.
+// Caller has to hold the lock.
+func (renderer *IndoorClimateRenderer) applyMoldRisk(roomId string, roomClimate *indoorCliemate, now time.Time) {
+
+	history, ok := renderer.moldHistory[roomId]
+	if !ok {
+		return
+	}
+
+	periods := history.periods
+	if history.above {
+		end := now
+		if maxAge, ok := renderer.maxAge[events.MeasurementType_HUMIDITY]; ok && now.Sub(history.lastTimestamp) > maxAge {
+			end = history.lastTimestamp.Add(maxAge)
+		}
+		periods = appendPeriod(periods, period{start: history.lastTimestamp, end: end})
+	}
+
+	windowStart := now.Add(-1 * renderer.moldWindow())
+	duration := time.Duration(0)
+	for _, moldPeriod := range periods {
+		if moldPeriod.start.Before(windowStart) {
+			moldPeriod.start = windowStart
+		}
+		if moldPeriod.end.After(moldPeriod.start) {
+			duration += moldPeriod.end.Sub(moldPeriod.start)
+		}
+	}
+
+	hours := int(duration.Hours())
+	roomClimate.MoldRisk = fmt.Sprintf("%d", hours)
+	switch {
+	case hours >= renderer.mold.actHours:
+		roomClimate.MoldRiskLevel = MOLD_RISK_ACT
+	case hours >= renderer.mold.watchHours:
+		roomClimate.MoldRiskLevel = MOLD_RISK_WATCH
+	default:
+		roomClimate.MoldRiskLevel = MOLD_RISK_OK
+	}
+}
+
+// moldWindow returns time range used to calculate mold risk.
+func (renderer *IndoorClimateRenderer) moldWindow() time.Duration {
+	return time.Duration(renderer.mold.days) * 24 * time.Hour
+}
+
+// appendPeriod appends passed period to given periods. It's merged with last period if they're adjacent.
+func appendPeriod(periods []period, newPeriod period) []period {
+
+	if len(periods) > 0 && periods[len(periods)-1].end.Equal(newPeriod.start) {
+		merged := append([]period{}, periods...)
+		merged[len(merged)-1].end = newPeriod.end
+		return merged
+	}
+	return append(append([]period{}, periods...), newPeriod)
+}
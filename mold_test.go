@@ -0,0 +1,91 @@
+package syncsign
+
+import (
+	"github.com/stretchr/testify/suite"
+	"testing"
+	"time"
+
+	config "github.com/tommzn/go-config"
+	events "github.com/tommzn/hdb-events-go"
+)
+
+type MoldTestSuite struct {
+	suite.Suite
+}
+
+func TestMoldTestSuite(t *testing.T) {
+	suite.Run(t, new(MoldTestSuite))
+}
+
+func (suite *MoldTestSuite) TestMoldConfigFromConfig() {
+
+	conf := loadConfigForTest(config.AsStringPtr("fixtures/testconfig13.yml"))
+	suite.Equal(moldConfig{threshold: 60, minTemperature: 10, days: 2, watchHours: 5, actHours: 20}, moldConfigFromConfig(conf, "hdb.indoorclimate.mold"))
+	suite.Equal(moldConfig{threshold: 70, minTemperature: 5, days: 7, watchHours: 12, actHours: 48}, moldConfigFromConfig(conf, "hdb.indoorclimate.xxx"))
+}
+
+func (suite *MoldTestSuite) TestMoldRisk() {
+
+	renderer := indoorClimateRendererForTest("fixtures/testconfig13.yml")
+	renderer.initIndoorClimateData()
+
+	roomClimate := renderer.sortedRoomClimateData()
+	suite.Len(roomClimate, 1)
+	suite.Equal("0", roomClimate[0].MoldRisk)
+	suite.Equal(MOLD_RISK_OK, roomClimate[0].MoldRiskLevel)
+
+	now := time.Now()
+	renderer.addAsIndoorClimateData(indoorClimateForTest("Device3", events.MeasurementType_TEMPERATURE, "20", now.Add(-31*time.Hour)))
+	renderer.addAsIndoorClimateData(indoorClimateForTest("Device3", events.MeasurementType_HUMIDITY, "70", now.Add(-30*time.Hour)))
+	renderer.addAsIndoorClimateData(indoorClimateForTest("Device3", events.MeasurementType_HUMIDITY, "50", now.Add(-20*time.Hour)))
+	renderer.addAsIndoorClimateData(indoorClimateForTest("Device3", events.MeasurementType_HUMIDITY, "80", now.Add(-10*time.Hour)))
+	renderer.addAsIndoorClimateData(indoorClimateForTest("Device3", events.MeasurementType_TEMPERATURE, "8", now.Add(-9*time.Hour)))
+	renderer.addAsIndoorClimateData(indoorClimateForTest("Device3", events.MeasurementType_HUMIDITY, "75", now.Add(-8*time.Hour)))
+
+	roomClimate2 := renderer.sortedRoomClimateData()
+	suite.Len(roomClimate2, 2)
+	suite.Equal("Room3", roomClimate2[1].RoomName)
+	suite.Equal("12", roomClimate2[1].MoldRisk)
+	suite.Equal(MOLD_RISK_WATCH, roomClimate2[1].MoldRiskLevel)
+
+	renderer.addAsIndoorClimateData(indoorClimateForTest("Device3", events.MeasurementType_TEMPERATURE, "15", now.Add(-70*time.Minute)))
+	renderer.addAsIndoorClimateData(indoorClimateForTest("Device3", events.MeasurementType_HUMIDITY, "70", now.Add(-1*time.Hour)))
+	roomClimate3 := renderer.sortedRoomClimateData()
+	suite.Equal("13", roomClimate3[1].MoldRisk)
+	suite.Equal(MOLD_RISK_WATCH, roomClimate3[1].MoldRiskLevel)
+}
+
+func (suite *MoldTestSuite) TestMoldRiskWindowAndMaxAge() {
+
+	renderer := indoorClimateRendererForTest("fixtures/testconfig13.yml")
+	now := time.Now()
+	renderer.moldHistory["3"] = moldHistory{
+		periods:       []period{period{start: now.Add(-60 * time.Hour), end: now.Add(-40 * time.Hour)}},
+		lastTimestamp: now.Add(-10 * time.Hour),
+		above:         true,
+	}
+
+	roomClimate := indoorCliemate{}
+	renderer.applyMoldRisk("3", &roomClimate, now)
+	suite.Equal("10", roomClimate.MoldRisk)
+	suite.Equal(MOLD_RISK_WATCH, roomClimate.MoldRiskLevel)
+
+	renderer.moldHistory["3"] = moldHistory{
+		periods: []period{period{start: now.Add(-30 * time.Hour), end: now.Add(-5 * time.Hour)}},
+	}
+	renderer.applyMoldRisk("3", &roomClimate, now)
+	suite.Equal("25", roomClimate.MoldRisk)
+	suite.Equal(MOLD_RISK_ACT, roomClimate.MoldRiskLevel)
+}
+
+func (suite *MoldTestSuite) TestAppendPeriod() {
+
+	now := time.Now()
+	periods := appendPeriod([]period{}, period{start: now.Add(-3 * time.Hour), end: now.Add(-2 * time.Hour)})
+	periods = appendPeriod(periods, period{start: now.Add(-2 * time.Hour), end: now.Add(-1 * time.Hour)})
+	suite.Len(periods, 1)
+	suite.Equal(now.Add(-1*time.Hour), periods[0].end)
+
+	periods = appendPeriod(periods, period{start: now.Add(-30 * time.Minute), end: now})
+	suite.Len(periods, 2)
+}
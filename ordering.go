@@ -0,0 +1,113 @@
+package syncsign
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// toRoomOrder converts passed config value into a room order. Defaults to order by display index.
+func toRoomOrder(order string) roomOrder {
+
+	switch roomOrder := roomOrder(strings.ToLower(order)); roomOrder {
+	case ORDER_NAME, ORDER_TEMPERATURE, ORDER_OUT_OF_RANGE:
+		return roomOrder
+	default:
+		return ORDER_DISPLAY_INDEX
+	}
+}
+
+// pinnedPosition converts passed config value into a position of a room, starting at 0.
+// Returns -1 if a room isn't pinned to a position.
+func pinnedPosition(position string) int {
+	if pos, err := strconv.Atoi(position); err == nil && pos >= 0 {
+		return pos
+	}
+	return -1
+}
+
+// sortRoomClimate sorts passed room climate by given order. Rooms which are equal for an order are sorted
+// by display index. Afterwards rooms pinned to a position are moved to it, all others fill remaining positions.
+func sortRoomClimate(roomClimate []indoorCliemate, order roomOrder, rooms map[string]room) []indoorCliemate {
+
+	sort.SliceStable(roomClimate, func(i, j int) bool {
+		switch order {
+		case ORDER_NAME:
+			if nameI, nameJ := strings.ToLower(roomClimate[i].RoomName), strings.ToLower(roomClimate[j].RoomName); nameI != nameJ {
+				return nameI < nameJ
+			}
+		case ORDER_TEMPERATURE:
+			tempI, errI := strconv.ParseFloat(roomClimate[i].Temperature, 64)
+			tempJ, errJ := strconv.ParseFloat(roomClimate[j].Temperature, 64)
+			if (errI == nil) != (errJ == nil) {
+				return errI == nil
+			}
+			if errI == nil && tempI != tempJ {
+				return tempI < tempJ
+			}
+		case ORDER_OUT_OF_RANGE:
+			if roomClimate[i].OutOfRange != roomClimate[j].OutOfRange {
+				return roomClimate[i].OutOfRange
+			}
+		}
+		return lessDisplayIndex(roomClimate[i].DisplayIndex, roomClimate[j].DisplayIndex)
+	})
+	return pinRooms(roomClimate, rooms)
+}
+
+// lessDisplayIndex compares display indexes numerically. Numeric indexes are sorted before all others,
+// which are compared as strings.
+func lessDisplayIndex(displayIndexI, displayIndexJ string) bool {
+
+	indexI, errI := strconv.Atoi(displayIndexI)
+	indexJ, errJ := strconv.Atoi(displayIndexJ)
+	switch {
+	case errI == nil && errJ == nil:
+		return indexI < indexJ
+	case errI == nil || errJ == nil:
+		return errI == nil
+	default:
+		return displayIndexI < displayIndexJ
+	}
+}
+
+// pinRooms moves rooms pinned to a position in passed sorted room climate to their position.
+// Pinned positions beyond the number of rooms are placed at the end.
+func pinRooms(roomClimate []indoorCliemate, rooms map[string]room) []indoorCliemate {
+
+	pinned := []indoorCliemate{}
+	unpinned := []indoorCliemate{}
+	for _, climate := range roomClimate {
+		if positionOf(climate.roomId, rooms) >= 0 {
+			pinned = append(pinned, climate)
+		} else {
+			unpinned = append(unpinned, climate)
+		}
+	}
+	if len(pinned) == 0 {
+		return roomClimate
+	}
+	sort.SliceStable(pinned, func(i, j int) bool {
+		return positionOf(pinned[i].roomId, rooms) < positionOf(pinned[j].roomId, rooms)
+	})
+
+	sorted := []indoorCliemate{}
+	for len(pinned) > 0 || len(unpinned) > 0 {
+		if len(pinned) > 0 && (positionOf(pinned[0].roomId, rooms) <= len(sorted) || len(unpinned) == 0) {
+			sorted = append(sorted, pinned[0])
+			pinned = pinned[1:]
+		} else {
+			sorted = append(sorted, unpinned[0])
+			unpinned = unpinned[1:]
+		}
+	}
+	return sorted
+}
+
+// positionOf returns the pinned position of passed room, -1 if it isn't pinned or has no config.
+func positionOf(roomId string, rooms map[string]room) int {
+	if roomCfg, ok := rooms[roomId]; ok {
+		return roomCfg.position
+	}
+	return -1
+}
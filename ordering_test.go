@@ -0,0 +1,98 @@
+package syncsign
+
+import (
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+type OrderingTestSuite struct {
+	suite.Suite
+}
+
+func TestOrderingTestSuite(t *testing.T) {
+	suite.Run(t, new(OrderingTestSuite))
+}
+
+func (suite *OrderingTestSuite) TestRoomOrderFromConfig() {
+
+	suite.Equal(ORDER_DISPLAY_INDEX, toRoomOrder(""))
+	suite.Equal(ORDER_DISPLAY_INDEX, toRoomOrder("displayIndex"))
+	suite.Equal(ORDER_NAME, toRoomOrder("Name"))
+	suite.Equal(ORDER_TEMPERATURE, toRoomOrder("temperature"))
+	suite.Equal(ORDER_OUT_OF_RANGE, toRoomOrder("outOfRange"))
+	suite.Equal(ORDER_DISPLAY_INDEX, toRoomOrder("xxx"))
+
+	suite.Equal(3, pinnedPosition("3"))
+	suite.Equal(-1, pinnedPosition("-3"))
+	suite.Equal(-1, pinnedPosition(""))
+
+	renderer := indoorClimateRendererForTest("fixtures/testconfig12.yml")
+	suite.Equal(ORDER_TEMPERATURE, renderer.order)
+	suite.Equal(0, renderer.roomCfg.rooms["3"].position)
+	suite.Equal(-1, renderer.roomCfg.rooms["1"].position)
+}
+
+func (suite *OrderingTestSuite) TestSortByDisplayIndex() {
+
+	roomClimate := sortRoomClimate(roomClimateForOrderingTest(), ORDER_DISPLAY_INDEX, map[string]room{})
+	suite.Equal([]string{"2", "9", "10", "11", "x"}, displayIndexes(roomClimate))
+}
+
+func (suite *OrderingTestSuite) TestSortByMode() {
+
+	roomClimate := sortRoomClimate(roomClimateForOrderingTest(), ORDER_NAME, map[string]room{})
+	suite.Equal([]string{"Bathroom", "bedroom", "Kitchen", "Living Room", "Office"}, roomNames(roomClimate))
+
+	roomClimate2 := sortRoomClimate(roomClimateForOrderingTest(), ORDER_TEMPERATURE, map[string]room{})
+	suite.Equal([]string{"11", "9", "2", "x", "10"}, displayIndexes(roomClimate2))
+
+	roomClimate3 := sortRoomClimate(roomClimateForOrderingTest(), ORDER_OUT_OF_RANGE, map[string]room{})
+	suite.Equal([]string{"10", "x", "2", "9", "11"}, displayIndexes(roomClimate3))
+}
+
+func (suite *OrderingTestSuite) TestPinnedPositions() {
+
+	rooms := map[string]room{
+		"a": room{Id: "a", position: 4},
+		"b": room{Id: "b", position: -1},
+		"c": room{Id: "c", position: 0},
+		"d": room{Id: "d", position: -1},
+		"e": room{Id: "e", position: 1},
+	}
+	roomClimate := sortRoomClimate(roomClimateForOrderingTest(), ORDER_DISPLAY_INDEX, rooms)
+	suite.Equal([]string{"11", "x", "2", "9", "10"}, displayIndexes(roomClimate))
+
+	rooms["a"] = room{Id: "a", position: 20}
+	roomClimate2 := sortRoomClimate(roomClimateForOrderingTest(), ORDER_DISPLAY_INDEX, rooms)
+	suite.Equal([]string{"11", "x", "2", "9", "10"}, displayIndexes(roomClimate2))
+
+	rooms["c"] = room{Id: "c", position: 3}
+	roomClimate3 := sortRoomClimate(roomClimateForOrderingTest(), ORDER_DISPLAY_INDEX, rooms)
+	suite.Equal([]string{"2", "x", "9", "11", "10"}, displayIndexes(roomClimate3))
+}
+
+func roomClimateForOrderingTest() []indoorCliemate {
+	return []indoorCliemate{
+		indoorCliemate{roomId: "a", DisplayIndex: "10", RoomName: "Living Room", Temperature: "--", OutOfRange: true},
+		indoorCliemate{roomId: "b", DisplayIndex: "2", RoomName: "Kitchen", Temperature: "21.5"},
+		indoorCliemate{roomId: "c", DisplayIndex: "11", RoomName: "bedroom", Temperature: "17.0"},
+		indoorCliemate{roomId: "d", DisplayIndex: "9", RoomName: "Office", Temperature: "20.5"},
+		indoorCliemate{roomId: "e", DisplayIndex: "x", RoomName: "Bathroom", Temperature: "23.0", OutOfRange: true},
+	}
+}
+
+func displayIndexes(roomClimate []indoorCliemate) []string {
+	indexes := []string{}
+	for _, climate := range roomClimate {
+		indexes = append(indexes, climate.DisplayIndex)
+	}
+	return indexes
+}
+
+func roomNames(roomClimate []indoorCliemate) []string {
+	names := []string{}
+	for _, climate := range roomClimate {
+		names = append(names, climate.RoomName)
+	}
+	return names
+}
@@ -0,0 +1,141 @@
+package preview
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+
+	syncsign "github.com/tommzn/hdb-renderer-syncsign"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+)
+
+// PNG returns an image of the layout in passed response as PNG.
+// Texts are drawn with a scaled bitmap font to approximate font sizes.
+func (preview *Preview) PNG(response *syncsign.Response) ([]byte, error) {
+
+	content, err := layoutContent(response)
+	if err != nil {
+		return nil, err
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, preview.width, preview.height))
+	fillRect(img, img.Bounds(), colorValue(string(content.Background.BgColor), "white"))
+	for _, item := range content.Items {
+		switch data := item.Data.(type) {
+		case *syncsign.TextData:
+			pngText(img, newTextElement(data))
+		case *syncsign.RectangleData:
+			pngRectangle(img, newRectangleElement(data))
+		}
+	}
+
+	buf := &bytes.Buffer{}
+	err = png.Encode(buf, img)
+	return buf.Bytes(), err
+}
+
+// pngRectangle draws passed rectangle. Stroke is drawn inside rectangle borders.
+func pngRectangle(img *image.RGBA, element rectangleElement) {
+
+	rect := image.Rect(element.x, element.y, element.x+element.width, element.y+element.height)
+	fillRect(img, rect, element.fillColor)
+
+	thickness := element.strokeThickness
+	if thickness <= 0 {
+		return
+	}
+	fillRect(img, image.Rect(rect.Min.X, rect.Min.Y, rect.Max.X, rect.Min.Y+thickness), element.strokeColor)
+	fillRect(img, image.Rect(rect.Min.X, rect.Max.Y-thickness, rect.Max.X, rect.Max.Y), element.strokeColor)
+	fillRect(img, image.Rect(rect.Min.X, rect.Min.Y, rect.Min.X+thickness, rect.Max.Y), element.strokeColor)
+	fillRect(img, image.Rect(rect.Max.X-thickness, rect.Min.Y, rect.Max.X, rect.Max.Y), element.strokeColor)
+}
+
+// pngText draws passed text, clipped at block borders. Icons are drawn as placeholder frame.
+func pngText(img *image.RGBA, element textElement) {
+
+	block := image.Rect(element.x, element.y, element.x+element.width, element.y+element.height).Intersect(img.Bounds())
+	blockImg := img.SubImage(block).(*image.RGBA)
+	fillRect(blockImg, block, element.background)
+
+	if element.isIcon {
+		size := element.iconSize()
+		x := element.x + element.iconX(size)
+		y := element.y + element.offsetY
+		frame := rectangleElement{x: x, y: y, width: size, height: size, fillColor: "none", strokeColor: element.color, strokeThickness: 1}
+		pngRectangle(blockImg, frame)
+		return
+	}
+
+	for idx, line := range element.lines {
+		glyphs := textMask(line)
+		width := glyphs.Bounds().Dx() * element.fontSize / glyphs.Bounds().Dy()
+		x := element.x + element.offsetX
+		switch element.align {
+		case "CENTER":
+			x += element.width/2 - width/2
+		case "RIGHT":
+			x += element.width - width
+		}
+		y := element.y + element.offsetY + idx*element.lineHeight()
+		drawScaled(blockImg, glyphs, image.Rect(x, y, x+width, y+element.fontSize), colorFor(element.color))
+	}
+}
+
+// textMask draws passed text with a bitmap font and returns it as alpha mask.
+func textMask(text string) *image.Alpha {
+
+	face := basicfont.Face7x13
+	width := font.MeasureString(face, text).Ceil()
+	if width == 0 {
+		width = 1
+	}
+	mask := image.NewAlpha(image.Rect(0, 0, width, face.Height))
+	drawer := font.Drawer{Dst: mask, Src: image.Opaque, Face: face, Dot: fixed.P(0, face.Ascent)}
+	drawer.DrawString(text)
+	return mask
+}
+
+// drawScaled draws passed mask scaled to given target rectangle, using nearest neighbor scaling.
+func drawScaled(img *image.RGBA, mask *image.Alpha, target image.Rectangle, c color.Color) {
+
+	srcWidth, srcHeight := mask.Bounds().Dx(), mask.Bounds().Dy()
+	if target.Dx() <= 0 || target.Dy() <= 0 {
+		return
+	}
+	for y := target.Min.Y; y < target.Max.Y; y++ {
+		for x := target.Min.X; x < target.Max.X; x++ {
+			if !(image.Point{X: x, Y: y}).In(img.Bounds()) {
+				continue
+			}
+			srcX := (x - target.Min.X) * srcWidth / target.Dx()
+			srcY := (y - target.Min.Y) * srcHeight / target.Dy()
+			if mask.AlphaAt(srcX, srcY).A > 127 {
+				img.Set(x, y, c)
+			}
+		}
+	}
+}
+
+// fillRect fills passed rectangle with given color. Nothing happens for color "none".
+func fillRect(img *image.RGBA, rect image.Rectangle, colorName string) {
+	if colorName == "none" {
+		return
+	}
+	draw.Draw(img, rect, image.NewUniform(colorFor(colorName)), image.Point{}, draw.Src)
+}
+
+// colorFor converts a color name into a color.
+func colorFor(colorName string) color.Color {
+	switch colorName {
+	case "white":
+		return color.White
+	case "red":
+		return color.RGBA{R: 0xd0, A: 0xff}
+	default:
+		return color.Black
+	}
+}
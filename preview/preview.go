@@ -0,0 +1,105 @@
+// Package preview converts layouts generated for SyncSign displays into SVG and PNG images.
+// Fonts are approximated, icons are drawn as placeholders.
+package preview
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+
+	syncsign "github.com/tommzn/hdb-renderer-syncsign"
+)
+
+// New returns a preview for a 7.5 inch SyncSign display.
+func New() *Preview {
+	return NewWithSize(DISPLAY_WIDTH, DISPLAY_HEIGHT)
+}
+
+// NewWithSize returns a preview for a display with passed size.
+func NewWithSize(width, height int) *Preview {
+	return &Preview{width: width, height: height}
+}
+
+// layoutContent returns content of first layout in passed response.
+func layoutContent(response *syncsign.Response) (*syncsign.RenderContent, error) {
+	if response == nil || len(response.Data) == 0 {
+		return nil, errors.New("No layout in response.")
+	}
+	return &response.Data[0].Content, nil
+}
+
+// newTextElement prepares passed text item for drawing.
+func newTextElement(data *syncsign.TextData) textElement {
+
+	element := textElement{
+		lines:      strings.Split(data.Text, "\n"),
+		x:          data.Block.X,
+		y:          data.Block.Y,
+		width:      data.Block.W,
+		height:     data.Block.H,
+		fontSize:   fontSize(data.Font, data.Block.H),
+		isIcon:     strings.HasPrefix(data.Font, "ICON_"),
+		align:      strings.ToUpper(data.TextAlign),
+		color:      colorValue(string(data.TextColor), "black"),
+		background: colorValue(string(data.BackgroundColor), "none"),
+	}
+	if data.Offset != nil {
+		element.offsetX = data.Offset.X
+		element.offsetY = data.Offset.Y
+	}
+	return element
+}
+
+// newRectangleElement prepares passed rectangle item for drawing.
+func newRectangleElement(data *syncsign.RectangleData) rectangleElement {
+	return rectangleElement{
+		x:               data.Block.X,
+		y:               data.Block.Y,
+		width:           data.Block.W,
+		height:          data.Block.H,
+		fillColor:       colorValue(string(data.FillColor), "none"),
+		strokeColor:     colorValue(string(data.StrokeColor), "black"),
+		strokeThickness: data.StrokeThickness,
+	}
+}
+
+// lineHeight returns distance between two lines of a text.
+func (element textElement) lineHeight() int {
+	return element.fontSize * 6 / 5
+}
+
+// ascent returns distance between top of a line and it's baseline.
+func (element textElement) ascent() int {
+	return element.fontSize * 4 / 5
+}
+
+// fontSize extracts font size from a SyncSign font name, e.g. 24 for "DDIN_24".
+// Font size of icon fonts and fonts without size depends on passed block height.
+func fontSize(font string, blockHeight int) int {
+
+	expression := regexp.MustCompile(`_([0-9]+)$`)
+	if matches := expression.FindStringSubmatch(font); len(matches) == 2 {
+		if size, err := strconv.Atoi(matches[1]); err == nil && size > 0 {
+			return size
+		}
+	}
+	if size := blockHeight * 3 / 4; size > 0 {
+		return size
+	}
+	return 16
+}
+
+// colorValue converts a SyncSign color into a color name used in images.
+func colorValue(color string, defaultColor string) string {
+	switch strings.ToUpper(color) {
+	case string(syncsign.COLOR_WHITE):
+		return "white"
+	case string(syncsign.COLOR_BLACK):
+		return "black"
+	case string(syncsign.COLOR_RED):
+		return "red"
+	default:
+		return defaultColor
+	}
+}
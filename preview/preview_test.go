@@ -0,0 +1,68 @@
+package preview
+
+import (
+	"bytes"
+	"image/color"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	syncsign "github.com/tommzn/hdb-renderer-syncsign"
+)
+
+type PreviewTestSuite struct {
+	suite.Suite
+}
+
+func TestPreviewTestSuite(t *testing.T) {
+	suite.Run(t, new(PreviewTestSuite))
+}
+
+func (suite *PreviewTestSuite) TestGenerateSVG() {
+
+	svg, err := New().SVG(responseForTest("fixtures/response.json"))
+	suite.Nil(err)
+
+	content := string(svg)
+	suite.True(strings.HasPrefix(content, `<svg xmlns="http://www.w3.org/2000/svg" width="880" height="528"`))
+	suite.True(strings.Contains(content, `<rect x="10" y="10" width="100" height="50" fill="red" stroke="black" stroke-width="2"/>`))
+	suite.True(strings.Contains(content, `<svg x="200" y="10" width="300" height="40">`))
+	suite.True(strings.Contains(content, `<text x="155" y="21" font-family="sans-serif" font-size="24" text-anchor="middle" fill="black">23.5° &lt;Living Room&gt;</text>`))
+	suite.True(strings.Contains(content, `<title>U+F240</title>`))
+	suite.True(strings.HasSuffix(content, "</svg>"))
+}
+
+func (suite *PreviewTestSuite) TestGeneratePNG() {
+
+	content, err := NewWithSize(700, 100).PNG(responseForTest("fixtures/response.json"))
+	suite.Nil(err)
+
+	img, err := png.Decode(bytes.NewReader(content))
+	suite.Nil(err)
+	suite.Equal(700, img.Bounds().Dx())
+	suite.Equal(100, img.Bounds().Dy())
+
+	suite.Equal(color.RGBAModel.Convert(color.White), color.RGBAModel.Convert(img.At(0, 0)))
+	suite.Equal(color.RGBAModel.Convert(color.Black), color.RGBAModel.Convert(img.At(10, 10)))
+	suite.Equal(color.RGBAModel.Convert(colorFor("red")), color.RGBAModel.Convert(img.At(50, 30)))
+	// Right aligned icon placeholder, size depends on block height
+	suite.Equal(color.RGBAModel.Convert(colorFor("red")), color.RGBAModel.Convert(img.At(626, 10)))
+}
+
+func (suite *PreviewTestSuite) TestWithoutLayout() {
+
+	_, err := New().SVG(&syncsign.Response{})
+	suite.NotNil(err)
+
+	_, err = New().PNG(nil)
+	suite.NotNil(err)
+}
+
+func (suite *PreviewTestSuite) TestFontSize() {
+
+	suite.Equal(24, fontSize("DDIN_24", 50))
+	suite.Equal(32, fontSize("KAUSHAN_SCRIPT_32", 50))
+	suite.Equal(43, fontSize("ICON_WEATHER", 58))
+	suite.Equal(16, fontSize("ICON_FA_SOLID", 0))
+}
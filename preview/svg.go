@@ -0,0 +1,115 @@
+package preview
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"strings"
+
+	syncsign "github.com/tommzn/hdb-renderer-syncsign"
+)
+
+// SVG returns an image of the layout in passed response as SVG.
+func (preview *Preview) SVG(response *syncsign.Response) ([]byte, error) {
+
+	content, err := layoutContent(response)
+	if err != nil {
+		return nil, err
+	}
+
+	buf := &bytes.Buffer{}
+	fmt.Fprintf(buf, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`,
+		preview.width, preview.height, preview.width, preview.height)
+	fmt.Fprintf(buf, `<rect width="100%%" height="100%%" fill="%s"/>`, colorValue(string(content.Background.BgColor), "white"))
+	for _, item := range content.Items {
+		switch data := item.Data.(type) {
+		case *syncsign.TextData:
+			svgText(buf, newTextElement(data))
+		case *syncsign.RectangleData:
+			svgRectangle(buf, newRectangleElement(data))
+		}
+	}
+	buf.WriteString("</svg>")
+	return buf.Bytes(), nil
+}
+
+// svgRectangle writes passed rectangle to given buffer.
+func svgRectangle(buf *bytes.Buffer, element rectangleElement) {
+
+	stroke := "none"
+	if element.strokeThickness > 0 {
+		stroke = element.strokeColor
+	}
+	fmt.Fprintf(buf, `<rect x="%d" y="%d" width="%d" height="%d" fill="%s" stroke="%s" stroke-width="%d"/>`,
+		element.x, element.y, element.width, element.height, element.fillColor, stroke, element.strokeThickness)
+}
+
+// svgText writes passed text to given buffer. A nested SVG element is used to clip text at block borders.
+// Icons are drawn as dashed placeholder, glyph code is added as title.
+func svgText(buf *bytes.Buffer, element textElement) {
+
+	fmt.Fprintf(buf, `<svg x="%d" y="%d" width="%d" height="%d">`, element.x, element.y, element.width, element.height)
+	if element.background != "none" {
+		fmt.Fprintf(buf, `<rect width="100%%" height="100%%" fill="%s"/>`, element.background)
+	}
+
+	if element.isIcon {
+		size := element.iconSize()
+		fmt.Fprintf(buf, `<rect x="%d" y="%d" width="%d" height="%d" fill="none" stroke="%s" stroke-dasharray="4 2"><title>%s</title></rect>`,
+			element.iconX(size), element.offsetY, size, size, element.color, glyphCodes(strings.Join(element.lines, "")))
+	} else {
+		anchor, x := element.svgTextAnchor()
+		for idx, line := range element.lines {
+			fmt.Fprintf(buf, `<text x="%d" y="%d" font-family="sans-serif" font-size="%d" text-anchor="%s" fill="%s">`,
+				x, element.offsetY+element.ascent()+idx*element.lineHeight(), element.fontSize, anchor, element.color)
+			xml.EscapeText(buf, []byte(line))
+			buf.WriteString("</text>")
+		}
+	}
+	buf.WriteString("</svg>")
+}
+
+// svgTextAnchor returns SVG text anchor and x position depending on text alignment.
+func (element textElement) svgTextAnchor() (string, int) {
+	switch element.align {
+	case "CENTER":
+		return "middle", element.width/2 + element.offsetX
+	case "RIGHT":
+		return "end", element.width + element.offsetX
+	default:
+		return "start", element.offsetX
+	}
+}
+
+// iconSize returns size of an icon placeholder, limited by block size.
+func (element textElement) iconSize() int {
+	size := element.fontSize
+	if size > element.height {
+		size = element.height
+	}
+	if size > element.width {
+		size = element.width
+	}
+	return size
+}
+
+// iconX returns x position of an icon placeholder with passed size, depending on text alignment.
+func (element textElement) iconX(size int) int {
+	switch element.align {
+	case "CENTER":
+		return (element.width-size)/2 + element.offsetX
+	case "RIGHT":
+		return element.width - size + element.offsetX
+	default:
+		return element.offsetX
+	}
+}
+
+// glyphCodes returns unicode code points of passed text, e.g. "U+F240".
+func glyphCodes(text string) string {
+	codes := []string{}
+	for _, glyph := range text {
+		codes = append(codes, fmt.Sprintf("U+%04X", glyph))
+	}
+	return strings.Join(codes, " ")
+}
@@ -0,0 +1,13 @@
+package preview
+
+import (
+	"io/ioutil"
+
+	syncsign "github.com/tommzn/hdb-renderer-syncsign"
+)
+
+func responseForTest(fileName string) *syncsign.Response {
+	content, _ := ioutil.ReadFile(fileName)
+	response, _ := syncsign.ParseResponse(string(content))
+	return response
+}
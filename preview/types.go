@@ -0,0 +1,37 @@
+package preview
+
+// Preview generates images of SyncSign layouts.
+type Preview struct {
+	width, height int
+}
+
+const (
+	// DISPLAY_WIDTH is the width of a 7.5 inch SyncSign display in pixel.
+	DISPLAY_WIDTH = 880
+	// DISPLAY_HEIGHT is the height of a 7.5 inch SyncSign display in pixel.
+	DISPLAY_HEIGHT = 528
+)
+
+// textElement is a text item prepared for drawing.
+type textElement struct {
+	lines      []string
+	x, y       int
+	width      int
+	height     int
+	offsetX    int
+	offsetY    int
+	fontSize   int
+	isIcon     bool
+	align      string
+	color      string
+	background string
+}
+
+// rectangleElement is a rectangle item prepared for drawing.
+type rectangleElement struct {
+	x, y            int
+	width, height   int
+	fillColor       string
+	strokeColor     string
+	strokeThickness int
+}
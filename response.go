@@ -1,7 +1,9 @@
 package syncsign
 
 import (
+	"crypto/sha1"
 	"errors"
+	"fmt"
 
 	utils "github.com/tommzn/go-utils"
 	core "github.com/tommzn/hdb-renderer-core"
@@ -17,36 +19,102 @@ func NewResponseRenderer(template core.Template, nodeId string, itemRenderer []c
 	}
 }
 
+// NewResponseRendererWithContentHash returns a renderer for eInk main content which derives render id
+// from node id and generated items. Same content will always get the same render id, so displays
+// can skip refreshs if nothing has changed. Items of volatile renderers are not used for render id.
+func NewResponseRendererWithContentHash(template core.Template, nodeId string, itemRenderer []core.Renderer) core.Renderer {
+	return &ResponseRenderer{
+		template:            template,
+		nodeId:              nodeId,
+		itemRenderer:        itemRenderer,
+		contentHashRenderId: true,
+	}
+}
+
 // Content returns the main layout for eInk display which includes
 // renderer/node id and all passed items.
 func (renderer *ResponseRenderer) Content() (string, error) {
 
 	data := responseData{
-		RenderId: utils.NewId(),
+		RenderId: "",
 		NodeId:   renderer.nodeId,
 		Items:    "",
 	}
 
-	items, err := renderer.contentFromItemRenderer()
+	items, stableItems, err := renderer.collectItems()
 	if err != nil {
 		return "", err
 	}
-	if items == nil || *items == "" {
+	if len(items) == 0 {
 		return "", errors.New("No items has been rendered!")
 	}
-	data.Items = *items
+
+	content, err := marshalItems(items)
+	if err != nil {
+		return "", err
+	}
+	data.Items = content
+
+	data.RenderId, err = renderer.renderId(stableItems)
+	if err != nil {
+		return "", err
+	}
 	return renderer.template.RenderWith(data)
 }
 
-// ContentFromItemRenderer loops above all existing item renderers and returns a list of all generated items.
-func (renderer *ResponseRenderer) contentFromItemRenderer() (*string, error) {
+// Response returns the main layout for eInk display as response model.
+func (renderer *ResponseRenderer) Response() (*Response, error) {
+
+	content, err := renderer.Content()
+	if err != nil {
+		return nil, err
+	}
+	return ParseResponse(content)
+}
+
+// Items loops above all existing item renderers and returns a list of all generated items.
+func (renderer *ResponseRenderer) Items() ([]Item, error) {
+	items, _, err := renderer.collectItems()
+	return items, err
+}
 
-	content := ""
+// CollectItems returns items of all item renderers. Additionally all items which are not
+// generated by a volatile renderer are returned.
+func (renderer *ResponseRenderer) collectItems() ([]Item, []Item, error) {
+
+	items := []Item{}
+	stableItems := []Item{}
 	errorStack := utils.NewErrorStack()
 	for _, itemRenderer := range renderer.itemRenderer {
-		items, err := itemRenderer.Content()
+		newItems, err := itemsFromRenderer(itemRenderer)
 		errorStack.Append(err)
-		content = appendItems(content, items)
+		items = append(items, newItems...)
+		if volatileRenderer, ok := itemRenderer.(VolatileRenderer); !ok || !volatileRenderer.IsVolatile() {
+			stableItems = append(stableItems, newItems...)
+		}
+	}
+	return items, stableItems, errorStack.AsError()
+}
+
+// RenderId returns a new random render id. If content hash render ids are enabled
+// render id is derived from node id and passed items.
+func (renderer *ResponseRenderer) renderId(items []Item) (string, error) {
+
+	if !renderer.contentHashRenderId {
+		return utils.NewId(), nil
 	}
-	return &content, errorStack.AsError()
+	content, err := marshalItems(items)
+	if err != nil {
+		return "", err
+	}
+	return contentHashId(renderer.nodeId + "\n" + content), nil
+}
+
+// ContentHashId returns a name based id, formatted as UUID version 5, for passed content.
+func contentHashId(content string) string {
+	hash := sha1.Sum([]byte(content))
+	id := hash[:16]
+	id[6] = (id[6] & 0x0f) | 0x50
+	id[8] = (id[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", id[0:4], id[4:6], id[6:8], id[8:10], id[10:16])
 }
@@ -4,6 +4,7 @@ import (
 	"github.com/stretchr/testify/suite"
 	core "github.com/tommzn/hdb-renderer-core"
 	"testing"
+	"time"
 )
 
 type ResponseTestSuite struct {
@@ -31,7 +32,7 @@ func (suite *ResponseTestSuite) TestGenerateContent() {
 	// Replace renderer id and timestamp with default value for assertion
 	content = replaceUUID(content, "RenderId-1")
 	content = replaceTimeStamp(content, "TimeStamp-1")
-	assertTemplateHash(suite.Assert(), content, "b38fe380db159d013db97d4b601609521aad9bfd")
+	assertTemplateHash(suite.Assert(), content, "f1f14d2c039645caf6afd94a4ddc850acdd6ee85")
 }
 
 func (suite *ResponseTestSuite) TestGenerateContentWithoutItemRenderer() {
@@ -58,3 +59,38 @@ func (suite *ResponseTestSuite) TestGenerateContentWithFailingItemRenderer() {
 	suite.NotNil(err)
 	suite.Equal("", content)
 }
+
+func (suite *ResponseTestSuite) TestContentHashRenderId() {
+
+	tmpl := templateQithFileForTest("templates/response.json")
+	itemRenderer := []core.Renderer{
+		newRendererMock(false, false),
+		NewTimestampRenderer(templateQithFileForTest("templates/timestamp.json")),
+	}
+
+	renderer1 := NewResponseRendererWithContentHash(tmpl, "Node-1", itemRenderer)
+	response1, err := renderer1.(*ResponseRenderer).Response()
+	suite.Nil(err)
+	suite.Len(response1.Data[0].Content.Items, 3)
+	suite.Regexp("^[a-f0-9]{8}-[a-f0-9]{4}-5[a-f0-9]{3}-[89ab][a-f0-9]{3}-[a-f0-9]{12}$", response1.Data[0].RenderId)
+
+	time.Sleep(1100 * time.Millisecond)
+	response2, err := renderer1.(*ResponseRenderer).Response()
+	suite.Nil(err)
+	suite.Equal(response1.Data[0].RenderId, response2.Data[0].RenderId)
+
+	renderer2 := NewResponseRendererWithContentHash(tmpl, "Node-2", itemRenderer)
+	response3, err := renderer2.(*ResponseRenderer).Response()
+	suite.Nil(err)
+	suite.NotEqual(response1.Data[0].RenderId, response3.Data[0].RenderId)
+
+	renderer3 := NewResponseRendererWithContentHash(tmpl, "Node-1", itemRenderer[1:])
+	response4, err := renderer3.(*ResponseRenderer).Response()
+	suite.Nil(err)
+	suite.NotEqual(response1.Data[0].RenderId, response4.Data[0].RenderId)
+
+	renderer4 := NewResponseRenderer(tmpl, "Node-1", itemRenderer)
+	response5, err := renderer4.(*ResponseRenderer).Response()
+	suite.Nil(err)
+	suite.NotEqual(response1.Data[0].RenderId, response5.Data[0].RenderId)
+}
@@ -0,0 +1,113 @@
+package syncsign
+
+import (
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+
+	config "github.com/tommzn/go-config"
+	log "github.com/tommzn/go-log"
+	events "github.com/tommzn/hdb-events-go"
+)
+
+// slotsFromConfig reads template slots for measurement types. Measurement type can be given by name, e.g. "CO2",
+// or by it's number to map types which are not known to the used events version. Slot name defaults to lowercase type,
+// precision to 1 and format to "{value}{unit}". Slots with an unknown measurement type are skipped.
+func slotsFromConfig(conf config.Config, slotsConfigKey string, logger log.Logger) map[events.MeasurementType]measurementSlot {
+
+	slots := make(map[events.MeasurementType]measurementSlot)
+	for _, slotCfg := range conf.GetAsSliceOfMaps(slotsConfigKey) {
+		measurementType, ok := toMeasurementType(slotCfg["type"])
+		if !ok {
+			logger.Errorf("Unknown measurement type for slot: %s", slotCfg["type"])
+			continue
+		}
+		slot := measurementSlot{
+			name:      strings.ToLower(measurementType.String()),
+			unit:      slotCfg["unit"],
+			format:    "{value}{unit}",
+			precision: 1,
+		}
+		if name, ok := slotCfg["name"]; ok && name != "" {
+			slot.name = name
+		}
+		if format, ok := slotCfg["format"]; ok && format != "" {
+			slot.format = format
+		}
+		if precision, err := strconv.Atoi(slotCfg["precision"]); err == nil {
+			slot.precision = forcePositive(precision)
+		}
+		slots[measurementType] = slot
+	}
+	return slots
+}
+
+// toMeasurementType converts passed measurement type name or number into a measurement type.
+func toMeasurementType(measurementType string) (events.MeasurementType, bool) {
+
+	measurementType = strings.TrimSpace(measurementType)
+	if value, ok := events.MeasurementType_value[strings.ToUpper(measurementType)]; ok {
+		return events.MeasurementType(value), true
+	}
+	if value, err := strconv.Atoi(measurementType); err == nil && value >= 0 {
+		return events.MeasurementType(value), true
+	}
+	return 0, false
+}
+
+// valueOf formats passed value for this slot. Values which aren't a number are used unchanged.
+func (slot measurementSlot) valueOf(value string) SlotValue {
+
+	if floatValue, err := strconv.ParseFloat(value, 64); err == nil {
+		value = formatWithPrecision(floatValue, slot.precision)
+	}
+	return SlotValue{
+		Value: value,
+		Unit:  slot.unit,
+		Text:  strings.NewReplacer("{value}", value, "{unit}", slot.unit).Replace(slot.format),
+	}
+}
+
+// defaultSlots returns placeholder values for all configured slots.
+func (renderer *IndoorClimateRenderer) defaultSlots() map[string]SlotValue {
+
+	slots := make(map[string]SlotValue)
+	for _, slot := range renderer.slots {
+		slots[slot.name] = SlotValue{Value: "--", Unit: slot.unit, Text: "--"}
+	}
+	return slots
+}
+
+// withSlotValue returns a copy of passed slots with given value assigned to the slot of a measurement type.
+// Slots are copied because room climate snapshots share them. Caller has to hold the lock.
+func (renderer *IndoorClimateRenderer) withSlotValue(slots map[string]SlotValue, measurementType events.MeasurementType, value string) map[string]SlotValue {
+
+	slot, ok := renderer.slots[measurementType]
+	if !ok {
+		return slots
+	}
+	slotValues := make(map[string]SlotValue, len(slots)+1)
+	for name, slotValue := range slots {
+		slotValues[name] = slotValue
+	}
+	slotValues[slot.name] = slot.valueOf(value)
+	return slotValues
+}
+
+// slotsText joins texts of passed slots, sorted by name and separated by a space. Text is escaped to be used
+// as a JSON string, because slot format and unit are taken from config.
+func slotsText(slots map[string]SlotValue) string {
+
+	names := make([]string, 0, len(slots))
+	for name := range slots {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	texts := make([]string, 0, len(names))
+	for _, name := range names {
+		texts = append(texts, slots[name].Text)
+	}
+	escaped, _ := json.Marshal(strings.Join(texts, " "))
+	return string(escaped[1 : len(escaped)-1])
+}
@@ -0,0 +1,94 @@
+package syncsign
+
+import (
+	"github.com/stretchr/testify/suite"
+	"testing"
+	"time"
+
+	config "github.com/tommzn/go-config"
+	events "github.com/tommzn/hdb-events-go"
+)
+
+type SlotsTestSuite struct {
+	suite.Suite
+}
+
+func TestSlotsTestSuite(t *testing.T) {
+	suite.Run(t, new(SlotsTestSuite))
+}
+
+func (suite *SlotsTestSuite) TestSlotsFromConfig() {
+
+	conf := loadConfigForTest(config.AsStringPtr("fixtures/testconfig20.yml"))
+	slots := slotsFromConfig(conf, "hdb.indoorclimate.slots", loggerForTest())
+	suite.Len(slots, 2)
+	suite.Equal(measurementSlot{name: "co2", unit: "ppm", format: "{value} {unit}", precision: 0}, slots[events.MeasurementType(7)])
+	suite.Equal(measurementSlot{name: "battery", unit: "%", format: "{value}{unit}", precision: 1}, slots[events.MeasurementType_BATTERY])
+
+	suite.Len(slotsFromConfig(conf, "hdb.indoorclimate.xxx", loggerForTest()), 0)
+}
+
+func (suite *SlotsTestSuite) TestToMeasurementType() {
+
+	measurementType, ok := toMeasurementType("Humidity")
+	suite.True(ok)
+	suite.Equal(events.MeasurementType_HUMIDITY, measurementType)
+
+	measurementType, ok = toMeasurementType(" 12 ")
+	suite.True(ok)
+	suite.Equal(events.MeasurementType(12), measurementType)
+
+	_, ok = toMeasurementType("co2")
+	suite.False(ok)
+	_, ok = toMeasurementType("-1")
+	suite.False(ok)
+}
+
+func (suite *SlotsTestSuite) TestSlotValue() {
+
+	slot := measurementSlot{name: "pressure", unit: "hPa", format: "{value} {unit}", precision: 1}
+	suite.Equal(SlotValue{Value: "1013.3", Unit: "hPa", Text: "1013.3 hPa"}, slot.valueOf("1013.27"))
+	suite.Equal(SlotValue{Value: "n/a", Unit: "hPa", Text: "n/a hPa"}, slot.valueOf("n/a"))
+}
+
+func (suite *SlotsTestSuite) TestAssignSlotValues() {
+
+	renderer := indoorClimateRendererForTest("fixtures/testconfig20.yml")
+	renderer.initIndoorClimateData()
+
+	roomClimate := renderer.sortedRoomClimateData()
+	suite.Len(roomClimate, 1)
+	suite.Equal(SlotValue{Value: "--", Unit: "ppm", Text: "--"}, roomClimate[0].Slots["co2"])
+	suite.Equal("23.0%", roomClimate[0].Slots["battery"].Text)
+
+	now := time.Now()
+	renderer.addAsIndoorClimateData(indoorClimateForTest("Device1", events.MeasurementType(7), "845.6", now))
+	renderer.addAsIndoorClimateData(indoorClimateForTest("Device1", events.MeasurementType(8), "12", now))
+
+	roomClimate2 := renderer.sortedRoomClimateData()
+	suite.Len(roomClimate2[0].Slots, 2)
+	suite.Equal(SlotValue{Value: "846", Unit: "ppm", Text: "846 ppm"}, roomClimate2[0].Slots["co2"])
+	suite.Equal("--", roomClimate[0].Slots["co2"].Value)
+}
+
+func (suite *SlotsTestSuite) TestRenderSlots() {
+
+	renderer := indoorClimateRendererForTest("fixtures/testconfig20.yml")
+
+	items, err := itemsFromRenderer(renderer)
+	suite.Nil(err)
+	suite.Len(items, 8)
+	slots := items[7].Data.(*TextData)
+	suite.Equal("hdb.indoorclimate.slots.1", slots.Id)
+	suite.Equal("23.0% --", slots.Text)
+}
+
+func (suite *SlotsTestSuite) TestSlotsText() {
+
+	slots := map[string]SlotValue{
+		"pressure": {Text: "1013 \"hPa\""},
+		"co2":      {Text: "846 ppm"},
+	}
+	suite.Equal("846 ppm 1013 \\\"hPa\\\"", slotsText(slots))
+	suite.Equal("", slotsText(map[string]SlotValue{}))
+}
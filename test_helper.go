@@ -54,6 +54,16 @@ func indoorClimateRendererForTest(configFile string) *IndoorClimateRenderer {
 	return NewIndoorClimateRenderer(loadConfigForTest(config.AsStringPtr(configFile)), loggerForTest(), templateForTest(), datasource)
 }
 
+func indoorClimateRendererWithoutDataForTest(configFile string) *IndoorClimateRenderer {
+	datasource := newDataSourceMock(false, false, make(map[hdbcore.DataSource][]proto.Message))
+	return NewIndoorClimateRenderer(loadConfigForTest(config.AsStringPtr(configFile)), loggerForTest(), templateForTest(), datasource)
+}
+
+func indoorClimateRendererWithTemplatesForTest(configFile string, templates IndoorClimateTemplates) *IndoorClimateRenderer {
+	datasource := newDataSourceMock(false, false, indoorClimateDataForTest())
+	return NewIndoorClimateRendererWithTemplates(loadConfigForTest(config.AsStringPtr(configFile)), "hdb.indoorclimate", loggerForTest(), templates, datasource)
+}
+
 func indoorClimateRendererWithDataSourceErrorForTest(configFile string) *IndoorClimateRenderer {
 	datasource := newDataSourceMock(true, true, indoorClimateDataForTest())
 	return NewIndoorClimateRenderer(loadConfigForTest(config.AsStringPtr(configFile)), loggerForTest(), templateForTest(), datasource)
@@ -121,6 +131,15 @@ func indoorClimateDataForTest() map[hdbcore.DataSource][]proto.Message {
 	return events
 }
 
+func indoorClimateForTest(deviceId string, measurementType events.MeasurementType, value string, timestamp time.Time) *events.IndoorClimate {
+	return &events.IndoorClimate{
+		Timestamp: timestamppb.New(timestamp),
+		DeviceId:  deviceId,
+		Type:      measurementType,
+		Value:     value,
+	}
+}
+
 func fixturesForBillingReportRenderer() map[hdbcore.DataSource][]proto.Message {
 	events := make(map[hdbcore.DataSource][]proto.Message)
 	events[hdbcore.DATASOURCE_BILLINGREPORT] = billingReportForTest()
@@ -167,6 +186,15 @@ func weatherRendererForTest(configFile string) *WeatherRenderer {
 	return NewWeatherRenderer(conf, loggerForTest(), currentWeatherTemplate, forecastWeatherTemplate, datasource)
 }
 
+func ventilationRendererForTest(indoorClimate IndoorClimateProvider, weather WeatherProvider) *VentilationRenderer {
+	conf := loadConfigForTest(config.AsStringPtr("fixtures/testconfig14.yml"))
+	return NewVentilationRenderer(conf, loggerForTest(), templateQithFileForTest("templates/ventilation.json"), indoorClimate, weather)
+}
+
+func floatPtr(value float64) *float64 {
+	return &value
+}
+
 func fixturesForWeatherRenderer() map[hdbcore.DataSource][]proto.Message {
 	events := make(map[hdbcore.DataSource][]proto.Message)
 	events[hdbcore.DATASOURCE_WEATHER] = weatherDataForTest()
@@ -276,3 +304,13 @@ func replaceTimeStamp(content, newTimeStamp string) string {
 	}
 	return content
 }
+
+func comfortRendererForTest(comfort ComfortSummary) *ComfortRenderer {
+	conf := loadConfigForTest(config.AsStringPtr("fixtures/testconfig21.yml"))
+	return NewComfortRenderer(conf, loggerForTest(), templateQithFileForTest("templates/comfort.json"), comfort)
+}
+
+func lowBatteryRendererForTest(batteries BatteryReporter) *LowBatteryRenderer {
+	conf := loadConfigForTest(config.AsStringPtr("fixtures/testconfig18.yml"))
+	return NewLowBatteryRenderer(conf, loggerForTest(), templateQithFileForTest("templates/lowbattery.json"), batteries)
+}
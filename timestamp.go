@@ -17,3 +17,8 @@ func NewTimestampRenderer(template core.Template) core.Renderer {
 func (renderer *TimestampRenderer) Content() (string, error) {
 	return renderer.template.RenderWith(time.Now().Format("2006-01-02 15:04:05 MST"))
 }
+
+// IsVolatile returns always true, because timestamp changes with each request.
+func (renderer *TimestampRenderer) IsVolatile() bool {
+	return true
+}
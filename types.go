@@ -1,16 +1,28 @@
 package syncsign
 
 import (
+	"sync"
+	"time"
+
 	"github.com/golang/protobuf/proto"
 	log "github.com/tommzn/go-log"
 	events "github.com/tommzn/hdb-events-go"
 	core "github.com/tommzn/hdb-renderer-core"
 )
 
+// IndoorClimateTemplates contains templates used by an indoor climate renderer. Room template is used for
+// all rooms which don't have their own template in Rooms, by room id. GroupHeader is required if rooms are grouped.
+type IndoorClimateTemplates struct {
+	Room        core.Template
+	Rooms       map[string]core.Template
+	GroupHeader core.Template
+}
+
 type IndoorClimateRenderer struct {
 	originAnchor   core.Point
 	size           core.Size
 	spacing        core.Spacing
+	layout         gridLayout
 	datasource     core.DataSource
 	template       core.Template
 	logger         log.Logger
@@ -18,31 +30,305 @@ type IndoorClimateRenderer struct {
 	roomCfg        roomConfig
 	dataSourceChan <-chan proto.Message
 	timestapMgr    core.TimestampManager
+	maxAge         map[events.MeasurementType]time.Duration
+	staleColor     textColor
+	lastUpdates    map[string]map[events.MeasurementType]time.Time
+	location       *time.Location
+	dailyExtremes  map[string]map[events.MeasurementType]dailyExtreme
+	trend          trendConfig
+	history        map[string]map[events.MeasurementType][]reading
+	battery        batteryConfig
+	unmapped       map[string]UnmappedDevice
+	deviceValues   map[string]map[events.MeasurementType]deviceValue
+	allRooms       bool
+	order          roomOrder
+	precision      valuePrecision
+	unit           temperatureUnit
+	mold           moldConfig
+	moldHistory    map[string]moldHistory
+	roomTemplates  map[string]core.Template
+	headerTemplate core.Template
+	headerHeight   int
+	offlineAfter   time.Duration
+	deviceLastSeen map[string]time.Time
+	startTime      time.Time
+	slots          map[events.MeasurementType]measurementSlot
+	lock           sync.RWMutex
 }
 
 type indoorCliemate struct {
-	DisplayIndex     string
-	Temperature      string
-	Humidity         string
-	BatteryIcon      batteryLevelIcon
-	BatteryIconColor textColor
-	RoomName         string
-	Anchor           core.Point
+	roomId               string
+	temperature          *float64
+	humidity             *float64
+	DisplayIndex         string
+	Temperature          string
+	TemperatureMin       string
+	TemperatureMax       string
+	TemperatureColor     textColor
+	TemperatureAlert     bool
+	TemperatureTrend     trendDirection
+	TemperatureTrendIcon trendIcon
+	Humidity             string
+	HumidityMin          string
+	HumidityMax          string
+	HumidityColor        textColor
+	HumidityAlert        bool
+	HumidityTrend        trendDirection
+	HumidityTrendIcon    trendIcon
+	BatteryIcon          batteryLevelIcon
+	BatteryIconColor     textColor
+	RoomName             string
+	Anchor               core.Point
+	OutOfRange           bool
+	Stale                bool
+	LastUpdate           time.Time
+	DewPoint             string
+	AbsoluteHumidity     string
+	HeatIndex            string
+	MoldRisk             string
+	MoldRiskLevel        moldRiskLevel
+	Offline              bool
+	OfflineDevices       []string
+	Slots                map[string]SlotValue
+	SlotsText            string
+}
+
+// moldConfig defines humidity threshold and temperature above which mold can grow, the number of days
+// used to calculate mold risk and hours above threshold required for each mold risk level.
+type moldConfig struct {
+	threshold, minTemperature  float64
+	days, watchHours, actHours int
+}
+
+// moldHistory contains periods a room has been above mold humidity threshold and it's latest state.
+type moldHistory struct {
+	periods       []period
+	lastTimestamp time.Time
+	above         bool
+}
+
+// period is a time range between start and end.
+type period struct {
+	start, end time.Time
+}
+
+type moldRiskLevel string
+
+const (
+	MOLD_RISK_OK    moldRiskLevel = "ok"
+	MOLD_RISK_WATCH moldRiskLevel = "watch"
+	MOLD_RISK_ACT   moldRiskLevel = "act"
+)
+
+// measurementSlot maps a measurement type to a named template slot. Values are rendered with given
+// number of decimals and format, which can contain {value} and {unit} placeholders.
+type measurementSlot struct {
+	name, unit, format string
+	precision          int
+}
+
+// SlotValue is a formatted measurement of a template slot.
+type SlotValue struct {
+	Value, Unit, Text string
+}
+
+// valuePrecision defines number of decimals of temperature, humidity and climate values derived from them.
+type valuePrecision struct {
+	temperature, humidity                 int
+	dewPoint, absoluteHumidity, heatIndex int
+}
+
+// temperatureUnit defines the unit temperatures are rendered in. Sensors send temperatures in °C.
+type temperatureUnit string
+
+const (
+	UNIT_CELSIUS    temperatureUnit = "celsius"
+	UNIT_FAHRENHEIT temperatureUnit = "fahrenheit"
+)
+
+// RoomClimate is latest temperature and humidity of a room, shared with other widgets.
+// Values are nil if nothing has been received for a room, yet.
+type RoomClimate struct {
+	RoomId      string
+	RoomName    string
+	Temperature *float64
+	Humidity    *float64
+	Stale       bool
+	LastUpdate  time.Time
+}
+
+// DeviceBattery is latest battery level of a device, in percent, and the room it's assigned to.
+type DeviceBattery struct {
+	DeviceId   string
+	RoomId     string
+	RoomName   string
+	Percent    float64
+	LastUpdate time.Time
+}
+
+// OfflineDevice is a device assigned to a room which hasn't send data for a while. Last seen is
+// missing if nothing has been received from a device.
+type OfflineDevice struct {
+	DeviceId string     `json:"deviceId"`
+	RoomId   string     `json:"roomId"`
+	RoomName string     `json:"roomName"`
+	LastSeen *time.Time `json:"lastSeen,omitempty"`
+}
+
+// UnmappedDevice is an indoor climate device which isn't assigned to a room,
+// with it's latest values for each measurement type.
+type UnmappedDevice struct {
+	DeviceId string            `json:"deviceId"`
+	LastSeen time.Time         `json:"lastSeen"`
+	Values   map[string]string `json:"values"`
+}
+
+// dailyExtreme is the minimum and maximum value of a measurement type at given day.
+type dailyExtreme struct {
+	day      string
+	min, max float64
+}
+
+// reading is a single value of a measurement, received at given time.
+type reading struct {
+	timestamp time.Time
+	value     float64
+}
+
+// trendConfig defines the window used to calculate trends of measurements and the
+// change of a value, per measurement type, required to be rising or falling.
+type trendConfig struct {
+	window     time.Duration
+	thresholds map[events.MeasurementType]float64
 }
 
+type trendDirection string
+
+const (
+	TREND_RISING  trendDirection = "rising"
+	TREND_FALLING trendDirection = "falling"
+	TREND_STEADY  trendDirection = "steady"
+)
+
+type trendIcon string
+
+const (
+	TREND_ICON_RISING  trendIcon = "\uf062"
+	TREND_ICON_FALLING trendIcon = "\uf063"
+	TREND_ICON_STEADY  trendIcon = "\uf061"
+)
+
+// gridLayout defines how room elements are arranged. Elements are placed in given direction
+// and wrap into a new row or column after given number of columns.
+type gridLayout struct {
+	columns   int
+	direction layoutDirection
+}
+
+type layoutDirection string
+
+const (
+	LAYOUT_HORIZONTAL layoutDirection = "horizontal"
+	LAYOUT_VERTICAL   layoutDirection = "vertical"
+)
+
 type roomConfig struct {
-	rooms     map[string]room
-	deviceMap map[string]string
+	rooms        map[string]room
+	deviceMap    map[string]string
+	calibrations map[string]map[events.MeasurementType]calibration
+	groups       []roomGroup
+}
+
+// calibration corrects values of a device, calibrated value is value * scale + offset.
+type calibration struct {
+	offset, scale float64
 }
 
 type room struct {
 	Id, Name, DisplayIndex string
+	comfortRanges          map[events.MeasurementType]comfortRange
+	aggregation            aggregationStrategy
+	primaryDevice          string
+	position               int
+	anchor                 *core.Point
+	template               string
+	group                  string
+}
+
+// roomGroup is a named section of rooms, e.g. a floor, rendered with a header above it's rooms.
+type roomGroup struct {
+	id, name string
+}
+
+// roomSection contains rooms rendered below a group header. Rooms without a group are rendered without header.
+type roomSection struct {
+	header *groupHeader
+	rooms  []indoorCliemate
+}
+
+// groupHeader is used to render the header of a room group.
+type groupHeader struct {
+	Anchor       core.Point
+	GroupId      string
+	Name         string
+	DisplayIndex int
+}
+
+// roomOrder defines how rooms are sorted before they're rendered.
+type roomOrder string
+
+const (
+	ORDER_DISPLAY_INDEX roomOrder = "displayindex"
+	ORDER_NAME          roomOrder = "name"
+	ORDER_TEMPERATURE   roomOrder = "temperature"
+	ORDER_OUT_OF_RANGE  roomOrder = "outofrange"
+)
+
+// aggregationStrategy defines how values of several devices in a room are combined.
+type aggregationStrategy string
+
+const (
+	AGGREGATION_LATEST  aggregationStrategy = "latest"
+	AGGREGATION_AVERAGE aggregationStrategy = "average"
+	AGGREGATION_MIN     aggregationStrategy = "min"
+	AGGREGATION_MAX     aggregationStrategy = "max"
+	AGGREGATION_PRIMARY aggregationStrategy = "primary"
+)
+
+// deviceValue is latest value of a measurement type received from a device.
+type deviceValue struct {
+	deviceId  string
+	timestamp time.Time
+	value     string
+}
+
+// comfortRange defines minimum and maximum of comfortable values for a measurement type.
+type comfortRange struct {
+	min, max float64
 }
 
 type DisplayConfig struct {
-	displays map[string]struct{}
+	displays map[string]display
 }
 
+type display struct {
+	Id      string
+	Widgets []string
+}
+
+const (
+	WIDGET_INDOORCLIMATE = "indoorclimate"
+	WIDGET_BILLINGREPORT = "billingreport"
+	WIDGET_WEATHER       = "weather"
+	WIDGET_TIMESTAMP     = "timestamp"
+	WIDGET_VENTILATION   = "ventilation"
+	WIDGET_LOWBATTERY    = "lowbattery"
+	WIDGET_COMFORT       = "comfort"
+)
+
+// DEFAULT_GROUP_HEADER_TEMPLATE is used for group headers of an indoor climate widget without header template config.
+const DEFAULT_GROUP_HEADER_TEMPLATE = "indoorclimate_header.json"
+
 type TimestampRenderer struct {
 	template core.Template
 }
@@ -65,10 +351,33 @@ const (
 	BATTERY_LEVEL_0_4 batteryLevelIcon = "\uf244"
 )
 
+// batteryConfig defines how battery values are converted into icons. Voltage range is in millivolts.
+// Levels are sorted by threshold in descending order.
+type batteryConfig struct {
+	scale                  batteryScale
+	voltageMin, voltageMax float64
+	levels                 []batteryLevel
+}
+
+// batteryLevel defines icon and color used for battery values in percent at or above threshold.
+type batteryLevel struct {
+	threshold float64
+	icon      batteryLevelIcon
+	color     textColor
+}
+
+type batteryScale string
+
+const (
+	BATTERY_SCALE_PERCENT batteryScale = "percent"
+	BATTERY_SCALE_VOLTAGE batteryScale = "voltage"
+)
+
 type ResponseRenderer struct {
-	template     core.Template
-	nodeId       string
-	itemRenderer []core.Renderer
+	template            core.Template
+	nodeId              string
+	itemRenderer        []core.Renderer
+	contentHashRenderId bool
 }
 
 type responseData struct {
@@ -103,6 +412,7 @@ type BillingReportRenderer struct {
 	dataSourceChan  <-chan proto.Message
 	billingReport   *billingReportData
 	exchangeRates   map[string]*events.ExchangeRate
+	lock            sync.RWMutex
 }
 
 type WeatherRenderer struct {
@@ -117,6 +427,87 @@ type WeatherRenderer struct {
 	dataSourceChan         <-chan proto.Message
 	weatherData            *events.WeatherData
 	weatherIconMap         WeatherIconMap
+	lock                   sync.RWMutex
+}
+
+// VentilationRenderer generates ventilation advices for all rooms by comparing indoor climate with
+// current outdoor weather, both observed by other renderers.
+type VentilationRenderer struct {
+	template         core.Template
+	anchor           core.Point
+	size             core.Size
+	logger           log.Logger
+	indoorClimate    IndoorClimateProvider
+	weather          WeatherProvider
+	minTemperature   float64
+	temperatureDelta float64
+	unit             temperatureUnit
+	precision        int
+}
+
+// ventilationAdvice is used to render ventilation advice of a single room.
+type ventilationAdvice struct {
+	Anchor             core.Point
+	DisplayIndex       int
+	RoomName           string
+	IndoorTemperature  string
+	OutdoorTemperature string
+	Cooler             bool
+	Drier              bool
+	Advice             ventilation
+	AdviceIcon         ventilationIcon
+}
+
+type ventilation string
+
+const (
+	VENTILATION_OPEN    ventilation = "open"
+	VENTILATION_CLOSED  ventilation = "closed"
+	VENTILATION_UNKNOWN ventilation = "unknown"
+)
+
+type ventilationIcon string
+
+const (
+	VENTILATION_ICON_OPEN    ventilationIcon = "\uf52b"
+	VENTILATION_ICON_CLOSED  ventilationIcon = "\uf52a"
+	VENTILATION_ICON_UNKNOWN ventilationIcon = "\uf128"
+)
+
+// LowBatteryRenderer generates a list of all devices with a battery level below a threshold,
+// using battery levels observed by another renderer.
+type LowBatteryRenderer struct {
+	template  core.Template
+	anchor    core.Point
+	size      core.Size
+	logger    log.Logger
+	batteries BatteryReporter
+	threshold float64
+	limit     int
+}
+
+// lowBatteryData is used to render a single device with low battery.
+type lowBatteryData struct {
+	Anchor       core.Point
+	DisplayIndex int
+	DeviceId     string
+	RoomName     string
+	Percent      string
+}
+
+// ComfortRenderer generates an alert with the number of rooms outside of their comfort range,
+// using room climate observed by another renderer.
+type ComfortRenderer struct {
+	template core.Template
+	anchor   core.Point
+	logger   log.Logger
+	comfort  ComfortSummary
+}
+
+// comfortData is used to render number of rooms outside of their comfort range.
+type comfortData struct {
+	Anchor          core.Point
+	RoomsOutOfRange int
 }
 
 type weatherData struct {
@@ -133,3 +524,102 @@ type weatherData struct {
 type WeatherIconMap struct {
 	icons map[string]string
 }
+
+// ItemType defines the kind of an item in a SyncSign render layout.
+type ItemType string
+
+const (
+	ITEM_TEXT                  ItemType = "TEXT"
+	ITEM_RECTANGLE             ItemType = "RECTANGLE"
+	ITEM_QRCODE                ItemType = "QRCODE"
+	ITEM_BOTTOM_CUSTOM_BUTTONS ItemType = "BOTTOM_CUSTOM_BUTTONS"
+)
+
+// Item is a single element of a SyncSign render layout.
+// Depending on item type, data is a *TextData, *RectangleData, *QrCodeData or *ButtonsData.
+// Data of unknown item types is kept as json.RawMessage.
+type Item struct {
+	Type ItemType    `json:"type"`
+	Data interface{} `json:"data"`
+}
+
+// Block defines position and size of an item.
+type Block struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+	W int `json:"w"`
+	H int `json:"h"`
+}
+
+// Offset moves content of an item inside it's block.
+type Offset struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+// TextData is used for items of type TEXT.
+type TextData struct {
+	Text            string    `json:"text"`
+	Id              string    `json:"id,omitempty"`
+	TextColor       textColor `json:"textColor,omitempty"`
+	BackgroundColor textColor `json:"backgroundColor,omitempty"`
+	Font            string    `json:"font,omitempty"`
+	TextAlign       string    `json:"textAlign,omitempty"`
+	LineSpace       int       `json:"lineSpace,omitempty"`
+	Block           Block     `json:"block"`
+	Offset          *Offset   `json:"offset,omitempty"`
+}
+
+// RectangleData is used for items of type RECTANGLE.
+type RectangleData struct {
+	FillColor       textColor `json:"fillColor,omitempty"`
+	FillPattern     string    `json:"fillPattern,omitempty"`
+	StrokeColor     textColor `json:"strokeColor,omitempty"`
+	StrokeThickness int       `json:"strokeThickness,omitempty"`
+	StrokePattern   string    `json:"strokePattern,omitempty"`
+	Block           Block     `json:"block"`
+}
+
+// QrCodeData is used for items of type QRCODE.
+type QrCodeData struct {
+	Text     string `json:"text"`
+	Scale    int    `json:"scale,omitempty"`
+	Version  int    `json:"version,omitempty"`
+	Position Offset `json:"position"`
+}
+
+// ButtonsData is used for items of type BOTTOM_CUSTOM_BUTTONS.
+type ButtonsData struct {
+	List []Button `json:"list"`
+}
+
+// Button is a single custom button at the bottom of a display.
+type Button struct {
+	Title string `json:"title"`
+	Style string `json:"style,omitempty"`
+}
+
+// Response is the payload a SyncSign display receives for a render request.
+type Response struct {
+	Code int          `json:"code"`
+	Data []RenderData `json:"data"`
+}
+
+// RenderData contains the layout for a single node.
+type RenderData struct {
+	RenderId   string        `json:"renderId"`
+	NodeId     string        `json:"nodeId"`
+	IsRendered bool          `json:"isRendered"`
+	Content    RenderContent `json:"content"`
+}
+
+// RenderContent defines background and all items of a layout.
+type RenderContent struct {
+	Background Background `json:"background"`
+	Items      []Item     `json:"items"`
+}
+
+// Background of a layout.
+type Background struct {
+	BgColor textColor `json:"bgColor"`
+}
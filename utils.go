@@ -2,10 +2,15 @@ package syncsign
 
 import (
 	"fmt"
+	"math"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	config "github.com/tommzn/go-config"
+	log "github.com/tommzn/go-log"
+	events "github.com/tommzn/hdb-events-go"
 	core "github.com/tommzn/hdb-renderer-core"
 )
 
@@ -51,8 +56,9 @@ func spacingFromConfig(conf config.Config, spacingConfigKey string) core.Spacing
 func configForRooms(conf config.Config, configKey string) roomConfig {
 
 	roomsCfg := roomConfig{
-		rooms:     make(map[string]room),
-		deviceMap: make(map[string]string),
+		rooms:        make(map[string]room),
+		deviceMap:    make(map[string]string),
+		calibrations: make(map[string]map[events.MeasurementType]calibration),
 	}
 
 	rooms := conf.GetAsSliceOfMaps(configKey + ".rooms")
@@ -67,13 +73,30 @@ func configForRooms(conf config.Config, configKey string) roomConfig {
 				displayIndex = fmt.Sprintf("%d", idx)
 			}
 			roomsCfg.rooms[roomId] = room{
-				Id:           roomId,
-				Name:         roomName,
-				DisplayIndex: displayIndex,
+				Id:            roomId,
+				Name:          roomName,
+				DisplayIndex:  displayIndex,
+				comfortRanges: comfortRangesFromConfig(roomCfg),
+				aggregation:   toAggregationStrategy(roomCfg["aggregation"]),
+				primaryDevice: strings.ToUpper(roomCfg["primary"]),
+				position:      pinnedPosition(roomCfg["position"]),
+				anchor:        roomAnchorFromConfig(roomCfg),
+				template:      roomCfg["template"],
+				group:         roomCfg["group"],
 			}
 		}
 	}
 
+	for _, groupCfg := range conf.GetAsSliceOfMaps(configKey + ".groups") {
+		if groupId, ok := groupCfg["id"]; ok {
+			groupName, ok1 := groupCfg["name"]
+			if !ok1 {
+				groupName = groupId
+			}
+			roomsCfg.groups = append(roomsCfg.groups, roomGroup{id: groupId, name: groupName})
+		}
+	}
+
 	devices := conf.GetAsSliceOfMaps(configKey + ".devices")
 	for _, device := range devices {
 		if id, ok := device["id"]; ok {
@@ -81,11 +104,140 @@ func configForRooms(conf config.Config, configKey string) roomConfig {
 			if roomId, ok1 := device["roomId"]; ok1 {
 				roomsCfg.deviceMap[deviceId] = roomId
 			}
+			if calibrations := calibrationsFromConfig(device); len(calibrations) > 0 {
+				roomsCfg.calibrations[deviceId] = calibrations
+			}
 		}
 	}
 	return roomsCfg
 }
 
+// roomAnchorFromConfig reads anchorX and anchorY of a room config. Nil is returned if a room
+// doesn't have an anchor, missing or invalid values are used as 0.
+func roomAnchorFromConfig(roomCfg map[string]string) *core.Point {
+
+	anchorX, okX := roomCfg["anchorX"]
+	anchorY, okY := roomCfg["anchorY"]
+	if !okX && !okY {
+		return nil
+	}
+	x, _ := strconv.Atoi(anchorX)
+	y, _ := strconv.Atoi(anchorY)
+	return &core.Point{X: forcePositive(x), Y: forcePositive(y)}
+}
+
+// hasGroup returns true if passed group is defined by config.
+func (cfg roomConfig) hasGroup(groupId string) bool {
+	for _, group := range cfg.groups {
+		if group.id == groupId {
+			return true
+		}
+	}
+	return false
+}
+
+// gridLayoutFromConfig reads columns and direction of a grid layout from passed config.
+// Defaults to a single row of elements in horizontal direction.
+func gridLayoutFromConfig(conf config.Config, layoutConfigKey string) gridLayout {
+
+	columns := conf.GetAsInt(layoutConfigKey+".columns", config.AsIntPtr(0))
+	direction := layoutDirection(strings.ToLower(*conf.Get(layoutConfigKey+".direction", config.AsStringPtr(string(LAYOUT_HORIZONTAL)))))
+	if direction != LAYOUT_VERTICAL {
+		direction = LAYOUT_HORIZONTAL
+	}
+	return gridLayout{columns: forcePositive(*columns), direction: direction}
+}
+
+// locationFromConfig loads the timezone defined by passed config key, e.g. "Europe/Berlin".
+// Local timezone is used if nothing has been configured or given timezone is unknown.
+func locationFromConfig(conf config.Config, timezoneConfigKey string, logger log.Logger) *time.Location {
+
+	timezone := conf.Get(timezoneConfigKey, nil)
+	if timezone == nil || *timezone == "" {
+		return time.Local
+	}
+	location, err := time.LoadLocation(*timezone)
+	if err != nil {
+		logger.Errorf("Unable to load timezone %s, reason: %s", *timezone, err)
+		return time.Local
+	}
+	return location
+}
+
+// trendConfigFromConfig reads window and thresholds used to calculate trends of measurements.
+// Defaults to a window of one hour and thresholds of 0.5 for temperature and 2 for humidity.
+func trendConfigFromConfig(conf config.Config, trendConfigKey string) trendConfig {
+
+	window := conf.GetAsDuration(trendConfigKey+".window", config.AsDurationPtr(1*time.Hour))
+	thresholds := map[events.MeasurementType]float64{
+		events.MeasurementType_TEMPERATURE: 0.5,
+		events.MeasurementType_HUMIDITY:    2,
+	}
+	for measurementType := range thresholds {
+		thresholdConfigKey := trendConfigKey + ".threshold." + strings.ToLower(measurementType.String())
+		if threshold, err := strconv.ParseFloat(*conf.Get(thresholdConfigKey, config.AsStringPtr("")), 64); err == nil && threshold >= 0 {
+			thresholds[measurementType] = threshold
+		}
+	}
+	return trendConfig{window: *window, thresholds: thresholds}
+}
+
+// maxAgeFromConfig reads max age of indoor climate values for each measurement type, e.g. "2h" for "max_age.temperature".
+// Values of measurement types without max age will never expire.
+func maxAgeFromConfig(conf config.Config, maxAgeConfigKey string) map[events.MeasurementType]time.Duration {
+
+	maxAge := make(map[events.MeasurementType]time.Duration)
+	for measurementType, name := range events.MeasurementType_name {
+		if duration := conf.GetAsDuration(maxAgeConfigKey+"."+strings.ToLower(name), nil); duration != nil && *duration > 0 {
+			maxAge[events.MeasurementType(measurementType)] = *duration
+		}
+	}
+	return maxAge
+}
+
+// textColorFromConfig returns color defined by passed config key or given default color if nothing has been configured.
+func textColorFromConfig(conf config.Config, colorConfigKey string, defaultColor textColor) textColor {
+	return toTextColor(*conf.Get(colorConfigKey, config.AsStringPtr("")), defaultColor)
+}
+
+// toTextColor converts passed color name into a text color, given default color is used for unknown colors.
+func toTextColor(color string, defaultColor textColor) textColor {
+
+	switch colorValue := textColor(strings.ToUpper(color)); colorValue {
+	case COLOR_WHITE, COLOR_BLACK, COLOR_RED:
+		return colorValue
+	default:
+		return defaultColor
+	}
+}
+
+// comfortRangesFromConfig extracts comfort ranges for temperature and humidity from passed room config,
+// e.g. temperatureMin: "17" and temperatureMax: "19". A range can be defined by min or max only.
+func comfortRangesFromConfig(roomCfg map[string]string) map[events.MeasurementType]comfortRange {
+
+	comfortRanges := make(map[events.MeasurementType]comfortRange)
+	for _, measurementType := range []events.MeasurementType{events.MeasurementType_TEMPERATURE, events.MeasurementType_HUMIDITY} {
+		name := strings.ToLower(measurementType.String())
+		min, minErr := strconv.ParseFloat(roomCfg[name+"Min"], 64)
+		if minErr != nil {
+			min = math.Inf(-1)
+		}
+		max, maxErr := strconv.ParseFloat(roomCfg[name+"Max"], 64)
+		if maxErr != nil {
+			max = math.Inf(1)
+		}
+		if minErr == nil || maxErr == nil {
+			comfortRanges[measurementType] = comfortRange{min: min, max: max}
+		}
+	}
+	return comfortRanges
+}
+
+// contains returns true if passed value is within this comfort range.
+func (comfort comfortRange) contains(value float64) bool {
+	return value >= comfort.min && value <= comfort.max
+}
+
 // forcePositive will return 0 for all negative values and origin value for all others.
 func forcePositive(val int) int {
 	if val < 0 {
@@ -94,45 +246,186 @@ func forcePositive(val int) int {
 	return val
 }
 
-func formatTemperature(temperature string) string {
+// parseValue returns passed measurement value as number, nil if it's not a number.
+func parseValue(value string) *float64 {
+	if floatVal, err := strconv.ParseFloat(value, 64); err == nil {
+		return &floatVal
+	}
+	return nil
+}
+
+// formatTemperature formats passed temperature in °C in given unit with given number of decimals.
+// Values which aren't a number are returned unchanged.
+func formatTemperature(temperature string, unit temperatureUnit, decimals int) string {
 	if floatTemp, err := strconv.ParseFloat(temperature, 64); err == nil {
-		return fmt.Sprintf("%.1f", floatTemp)
+		return formatWithPrecision(toTemperatureUnit(floatTemp, unit), decimals)
 	}
 	return temperature
 }
 
-func formatHumidity(humidity string) string {
+// formatHumidity formats passed humidity with given number of decimals.
+// Values which aren't a number are returned unchanged.
+func formatHumidity(humidity string, decimals int) string {
 	if floatHum, err := strconv.ParseFloat(humidity, 64); err == nil {
-		return fmt.Sprintf("%.0f", floatHum)
+		return formatWithPrecision(floatHum, decimals)
 	}
 	return humidity
 }
 
-func batteryIcon(batteryValue string) batteryLevelIcon {
-	intVal := batteryValueToInt(batteryValue)
-	switch {
-	case intVal >= 90:
-		return BATTERY_LEVEL_4_4
-	case intVal >= 75:
-		return BATTERY_LEVEL_3_4
-	case intVal >= 50:
-		return BATTERY_LEVEL_2_4
-	case intVal >= 10:
-		return BATTERY_LEVEL_1_4
-	case intVal <= 10:
-		return BATTERY_LEVEL_0_4
-	default:
-		return BATTERY_LEVEL_0_4
+// toTemperatureUnit converts passed temperature in °C into given unit.
+func toTemperatureUnit(temperature float64, unit temperatureUnit) float64 {
+	if unit == UNIT_FAHRENHEIT {
+		return temperature*9/5 + 32
 	}
+	return temperature
+}
+
+// temperatureUnitFromConfig reads the unit temperatures are rendered in, default is celsius.
+func temperatureUnitFromConfig(conf config.Config, unitConfigKey string) temperatureUnit {
+	if unit := temperatureUnit(strings.ToLower(*conf.Get(unitConfigKey, config.AsStringPtr("")))); unit == UNIT_FAHRENHEIT {
+		return UNIT_FAHRENHEIT
+	}
+	return UNIT_CELSIUS
+}
+
+// precisionFromConfig reads number of decimals for temperature, humidity and derived climate values.
+// Default is 1 for temperature and derived values and 0 for humidity.
+func precisionFromConfig(conf config.Config, precisionConfigKey string) valuePrecision {
+	return valuePrecision{
+		temperature:      forcePositive(*conf.GetAsInt(precisionConfigKey+".temperature", config.AsIntPtr(1))),
+		humidity:         forcePositive(*conf.GetAsInt(precisionConfigKey+".humidity", config.AsIntPtr(0))),
+		dewPoint:         forcePositive(*conf.GetAsInt(precisionConfigKey+".dew_point", config.AsIntPtr(1))),
+		absoluteHumidity: forcePositive(*conf.GetAsInt(precisionConfigKey+".absolute_humidity", config.AsIntPtr(1))),
+		heatIndex:        forcePositive(*conf.GetAsInt(precisionConfigKey+".heat_index", config.AsIntPtr(1))),
+	}
+}
+
+// calibrationsFromConfig reads offset and scale of temperature and humidity of a device config.
+// Scale defaults to 1 and offset to 0, nothing is returned for a measurement type without calibration.
+func calibrationsFromConfig(deviceCfg map[string]string) map[events.MeasurementType]calibration {
 
+	calibrations := make(map[events.MeasurementType]calibration)
+	for measurementType, prefix := range map[events.MeasurementType]string{
+		events.MeasurementType_TEMPERATURE: "temperature",
+		events.MeasurementType_HUMIDITY:    "humidity",
+	} {
+		offset := parseValue(deviceCfg[prefix+"Offset"])
+		scale := parseValue(deviceCfg[prefix+"Scale"])
+		if offset == nil && scale == nil {
+			continue
+		}
+		deviceCalibration := calibration{offset: 0, scale: 1}
+		if offset != nil {
+			deviceCalibration.offset = *offset
+		}
+		if scale != nil {
+			deviceCalibration.scale = *scale
+		}
+		calibrations[measurementType] = deviceCalibration
+	}
+	return calibrations
 }
 
-func batteryIconColor(batteryValue string) textColor {
-	intVal := batteryValueToInt(batteryValue)
-	if intVal <= 5 {
-		return COLOR_RED
+// calibrate applies calibration of passed device and measurement type to given value, rounded to 6 decimals
+// to avoid floating point artifacts. Values without calibration or which aren't a number are returned unchanged.
+func (cfg roomConfig) calibrate(deviceId string, measurementType events.MeasurementType, value string) string {
+
+	deviceCalibration, ok := cfg.calibrations[deviceId][measurementType]
+	if !ok {
+		return value
+	}
+	floatVal := parseValue(value)
+	if floatVal == nil {
+		return value
 	}
-	return COLOR_BLACK
+	calibrated := *floatVal*deviceCalibration.scale + deviceCalibration.offset
+	return strconv.FormatFloat(math.Round(calibrated*1e6)/1e6, 'f', -1, 64)
+}
+
+// batteryConfigFromConfig reads scale, voltage range in millivolts, default is 2500 to 3000, and levels used to convert
+// battery values into icons. Default levels are used if no valid level has been configured.
+func batteryConfigFromConfig(conf config.Config, batteryConfigKey string) batteryConfig {
+
+	batteryCfg := batteryConfig{
+		scale:      BATTERY_SCALE_PERCENT,
+		voltageMin: floatFromConfig(conf, batteryConfigKey+".voltage.min", 2500),
+		voltageMax: floatFromConfig(conf, batteryConfigKey+".voltage.max", 3000),
+		levels:     []batteryLevel{},
+	}
+	if scale := batteryScale(strings.ToLower(*conf.Get(batteryConfigKey+".scale", config.AsStringPtr("")))); scale == BATTERY_SCALE_VOLTAGE {
+		batteryCfg.scale = scale
+	}
+
+	for _, levelCfg := range conf.GetAsSliceOfMaps(batteryConfigKey + ".levels") {
+		threshold, err := strconv.ParseFloat(levelCfg["threshold"], 64)
+		if err != nil {
+			continue
+		}
+		icon, ok := levelCfg["icon"]
+		if !ok {
+			icon = string(BATTERY_LEVEL_0_4)
+		}
+		batteryCfg.levels = append(batteryCfg.levels, batteryLevel{
+			threshold: threshold,
+			icon:      batteryLevelIcon(icon),
+			color:     toTextColor(levelCfg["color"], COLOR_BLACK),
+		})
+	}
+	if len(batteryCfg.levels) == 0 {
+		batteryCfg.levels = defaultBatteryLevels()
+	}
+	sort.Slice(batteryCfg.levels, func(i, j int) bool {
+		return batteryCfg.levels[i].threshold > batteryCfg.levels[j].threshold
+	})
+	return batteryCfg
+}
+
+// defaultBatteryLevels returns battery levels for values in percent, values of 5% and below are red.
+func defaultBatteryLevels() []batteryLevel {
+	return []batteryLevel{
+		batteryLevel{threshold: 90, icon: BATTERY_LEVEL_4_4, color: COLOR_BLACK},
+		batteryLevel{threshold: 75, icon: BATTERY_LEVEL_3_4, color: COLOR_BLACK},
+		batteryLevel{threshold: 50, icon: BATTERY_LEVEL_2_4, color: COLOR_BLACK},
+		batteryLevel{threshold: 10, icon: BATTERY_LEVEL_1_4, color: COLOR_BLACK},
+		batteryLevel{threshold: 6, icon: BATTERY_LEVEL_0_4, color: COLOR_BLACK},
+		batteryLevel{threshold: 0, icon: BATTERY_LEVEL_0_4, color: COLOR_RED},
+	}
+}
+
+// levelFor returns the battery level of passed battery value. Lowest level is used for values
+// below all thresholds and for invalid values.
+func (batteryCfg batteryConfig) levelFor(batteryValue string) batteryLevel {
+
+	percent := batteryCfg.percent(batteryValue)
+	for _, level := range batteryCfg.levels {
+		if percent >= level.threshold {
+			return level
+		}
+	}
+	return batteryCfg.levels[len(batteryCfg.levels)-1]
+}
+
+// percent converts passed battery value into percent. Voltages in millivolts are mapped linear to percent by voltage range.
+func (batteryCfg batteryConfig) percent(batteryValue string) float64 {
+
+	if batteryCfg.scale != BATTERY_SCALE_VOLTAGE {
+		return float64(batteryValueToInt(batteryValue))
+	}
+
+	voltage, err := strconv.ParseFloat(batteryValue, 64)
+	if err != nil || batteryCfg.voltageMax <= batteryCfg.voltageMin {
+		return 0
+	}
+	percent := (voltage - batteryCfg.voltageMin) / (batteryCfg.voltageMax - batteryCfg.voltageMin) * 100
+	return math.Max(0, math.Min(100, percent))
+}
+
+// floatFromConfig returns float value of passed config key or given default value if it's missing or not a number.
+func floatFromConfig(conf config.Config, configKey string, defaultValue float64) float64 {
+	if value, err := strconv.ParseFloat(*conf.Get(configKey, config.AsStringPtr("")), 64); err == nil {
+		return value
+	}
+	return defaultValue
 }
 
 func batteryValueToInt(batteryValue string) int {
@@ -146,22 +439,6 @@ func batteryValueToInt(batteryValue string) int {
 	return 0
 }
 
-// AppendItems appends passed items to given content, separated by default JSON element separattor: ",".
-// Leasing separators in content, or trailing separators in items will be removed.
-func appendItems(items, newItems string) string {
-	contents := []string{items}
-	if newItems != "" {
-		contents = append(contents, newItems)
-	}
-	for idx, content := range contents {
-		content = strings.TrimSpace(content)
-		content = strings.TrimPrefix(content, ",")
-		content = strings.TrimSuffix(content, ",")
-		contents[idx] = content
-	}
-	return strings.Join(contents, ",")
-}
-
 func keyForExchangeRate(fromCurrency, toCurrency string) string {
 	return fmt.Sprintf("%s-%s", fromCurrency, toCurrency)
 }
@@ -3,8 +3,10 @@ package syncsign
 import (
 	"github.com/stretchr/testify/suite"
 	"testing"
+	"time"
 
 	config "github.com/tommzn/go-config"
+	events "github.com/tommzn/hdb-events-go"
 	core "github.com/tommzn/hdb-renderer-core"
 )
 
@@ -75,19 +77,122 @@ func (suite *UtilsTestSuite) TestGetRoomConfig() {
 	suite.Len(roomCfg.deviceMap, 3)
 }
 
+func (suite *UtilsTestSuite) TestGetGridLayoutFromConfig() {
+
+	testCases := map[string]gridLayout{
+		"fixtures/layouttest01.yml": gridLayout{columns: 3, direction: LAYOUT_VERTICAL},
+		"fixtures/layouttest02.yml": gridLayout{columns: 0, direction: LAYOUT_HORIZONTAL},
+		"fixtures/testconfig02.yml": gridLayout{columns: 0, direction: LAYOUT_HORIZONTAL},
+	}
+	for configFile, expectedLayout := range testCases {
+		conf := loadConfigForTest(config.AsStringPtr(configFile))
+		suite.Equal(expectedLayout, gridLayoutFromConfig(conf, "hdb.indoorclimate.layout"))
+	}
+}
+
+func (suite *UtilsTestSuite) TestGetLocationFromConfig() {
+
+	conf := loadConfigForTest(config.AsStringPtr("fixtures/testconfig08.yml"))
+	suite.Equal("Europe/Berlin", locationFromConfig(conf, "hdb.indoorclimate.timezone", loggerForTest()).String())
+	suite.Equal(time.Local, locationFromConfig(conf, "hdb.indoorclimate.xxx", loggerForTest()))
+	suite.Equal(time.Local, locationFromConfig(conf, "hdb.indoorclimate.stale_color", loggerForTest()))
+}
+
+func (suite *UtilsTestSuite) TestGetTrendConfigFromConfig() {
+
+	conf := loadConfigForTest(config.AsStringPtr("fixtures/testconfig08.yml"))
+	trend := trendConfigFromConfig(conf, "hdb.indoorclimate.trend")
+	suite.Equal(30*time.Minute, trend.window)
+	suite.Equal(0.3, trend.thresholds[events.MeasurementType_TEMPERATURE])
+	suite.Equal(5.0, trend.thresholds[events.MeasurementType_HUMIDITY])
+
+	defaultTrend := trendConfigFromConfig(conf, "hdb.indoorclimate.xxx")
+	suite.Equal(1*time.Hour, defaultTrend.window)
+	suite.Equal(0.5, defaultTrend.thresholds[events.MeasurementType_TEMPERATURE])
+	suite.Equal(2.0, defaultTrend.thresholds[events.MeasurementType_HUMIDITY])
+}
+
+func (suite *UtilsTestSuite) TestGetMaxAgeFromConfig() {
+
+	conf := loadConfigForTest(config.AsStringPtr("fixtures/testconfig08.yml"))
+	maxAge := maxAgeFromConfig(conf, "hdb.indoorclimate.max_age")
+	suite.Len(maxAge, 2)
+	suite.Equal(2*time.Hour, maxAge[events.MeasurementType_TEMPERATURE])
+	suite.Equal(30*time.Minute, maxAge[events.MeasurementType_HUMIDITY])
+
+	suite.Len(maxAgeFromConfig(conf, "hdb.indoorclimate.xxx"), 0)
+}
+
+func (suite *UtilsTestSuite) TestGetTextColorFromConfig() {
+
+	conf := loadConfigForTest(config.AsStringPtr("fixtures/testconfig08.yml"))
+	suite.Equal(COLOR_WHITE, textColorFromConfig(conf, "hdb.indoorclimate.stale_color", COLOR_RED))
+	suite.Equal(COLOR_RED, textColorFromConfig(conf, "hdb.indoorclimate.xxx", COLOR_RED))
+	suite.Equal(COLOR_BLACK, textColorFromConfig(conf, "hdb.indoorclimate.anchor.x", COLOR_BLACK))
+}
+
+func (suite *UtilsTestSuite) TestGetComfortRanges() {
+
+	conf := loadConfigForTest(config.AsStringPtr("fixtures/testconfig09.yml"))
+	roomCfg := configForRooms(conf, "hdb.indoorclimate")
+	suite.Len(roomCfg.rooms["1"].comfortRanges, 2)
+	suite.Equal(comfortRange{min: 18, max: 20.5}, roomCfg.rooms["1"].comfortRanges[events.MeasurementType_TEMPERATURE])
+
+	humidityRange := roomCfg.rooms["2"].comfortRanges[events.MeasurementType_HUMIDITY]
+	suite.True(humidityRange.contains(-10))
+	suite.True(humidityRange.contains(60))
+	suite.False(humidityRange.contains(60.1))
+
+	suite.Len(comfortRangesFromConfig(map[string]string{"temperatureMin": "abc"}), 0)
+}
+
 func (suite *UtilsTestSuite) TestFormatValues() {
 
-	suite.Equal("23.4", formatTemperature("23.4"))
-	suite.Equal("23.4", formatTemperature("23.42"))
-	suite.Equal("23.5", formatTemperature("23.47"))
-	suite.Equal("23.0", formatTemperature("23"))
-	suite.Equal("xxx", formatTemperature("xxx"))
+	suite.Equal("23.4", formatTemperature("23.4", UNIT_CELSIUS, 1))
+	suite.Equal("23.4", formatTemperature("23.42", UNIT_CELSIUS, 1))
+	suite.Equal("23.5", formatTemperature("23.47", UNIT_CELSIUS, 1))
+	suite.Equal("23.0", formatTemperature("23", UNIT_CELSIUS, 1))
+	suite.Equal("23.42", formatTemperature("23.42", UNIT_CELSIUS, 2))
+	suite.Equal("23", formatTemperature("23.42", UNIT_CELSIUS, 0))
+	suite.Equal("74.2", formatTemperature("23.42", UNIT_FAHRENHEIT, 1))
+	suite.Equal("32", formatTemperature("0", UNIT_FAHRENHEIT, 0))
+	suite.Equal("xxx", formatTemperature("xxx", UNIT_FAHRENHEIT, 1))
 
-	suite.Equal("23", formatHumidity("23.4"))
-	suite.Equal("23", formatHumidity("23.42"))
-	suite.Equal("24", formatHumidity("23.67"))
-	suite.Equal("23", formatHumidity("23"))
-	suite.Equal("xxx", formatHumidity("xxx"))
+	suite.Equal("23", formatHumidity("23.4", 0))
+	suite.Equal("23", formatHumidity("23.42", 0))
+	suite.Equal("24", formatHumidity("23.67", 0))
+	suite.Equal("23", formatHumidity("23", 0))
+	suite.Equal("23.7", formatHumidity("23.67", 1))
+	suite.Equal("xxx", formatHumidity("xxx", 0))
+}
+
+func (suite *UtilsTestSuite) TestFormatConfig() {
+
+	conf := loadConfigForTest(config.AsStringPtr("fixtures/testconfig15.yml"))
+	suite.Equal(UNIT_FAHRENHEIT, temperatureUnitFromConfig(conf, "hdb.indoorclimate.unit"))
+	suite.Equal(UNIT_CELSIUS, temperatureUnitFromConfig(conf, "hdb.indoorclimate.xxx"))
+	suite.Equal(valuePrecision{temperature: 2, humidity: 1, dewPoint: 1, absoluteHumidity: 1, heatIndex: 1}, precisionFromConfig(conf, "hdb.indoorclimate.precision"))
+
+	conf2 := loadConfigForTest(config.AsStringPtr("fixtures/testconfig12.yml"))
+	suite.Equal(valuePrecision{temperature: 1, humidity: 0, dewPoint: 2, absoluteHumidity: 0, heatIndex: 1}, precisionFromConfig(conf2, "hdb.indoorclimate.precision"))
+	suite.Equal(valuePrecision{temperature: 1, humidity: 0, dewPoint: 1, absoluteHumidity: 1, heatIndex: 1}, precisionFromConfig(conf2, "hdb.indoorclimate.xxx"))
+}
+
+func (suite *UtilsTestSuite) TestCalibration() {
+
+	conf := loadConfigForTest(config.AsStringPtr("fixtures/testconfig15.yml"))
+	roomCfg := configForRooms(conf, "hdb.indoorclimate")
+	suite.Len(roomCfg.calibrations, 1)
+	suite.Equal(calibration{offset: -0.8, scale: 1}, roomCfg.calibrations["DEVICE2"][events.MeasurementType_TEMPERATURE])
+	suite.Equal(calibration{offset: -2, scale: 1.1}, roomCfg.calibrations["DEVICE2"][events.MeasurementType_HUMIDITY])
+
+	suite.Equal("20.5", roomCfg.calibrate("DEVICE2", events.MeasurementType_TEMPERATURE, "21.3"))
+	suite.Equal("53", roomCfg.calibrate("DEVICE2", events.MeasurementType_HUMIDITY, "50"))
+	suite.Equal("97", roomCfg.calibrate("DEVICE2", events.MeasurementType_BATTERY, "97"))
+	suite.Equal("xxx", roomCfg.calibrate("DEVICE2", events.MeasurementType_HUMIDITY, "xxx"))
+	suite.Equal("21.3", roomCfg.calibrate("DEVICE1", events.MeasurementType_TEMPERATURE, "21.3"))
+
+	suite.Len(calibrationsFromConfig(map[string]string{"temperatureOffset": "abc"}), 0)
 }
 
 func (suite *UtilsTestSuite) TestParseBatteryValue() {
@@ -102,28 +207,56 @@ func (suite *UtilsTestSuite) TestParseBatteryValue() {
 
 func (suite *UtilsTestSuite) TestBatteryIcon() {
 
-	suite.Equal(COLOR_BLACK, batteryIconColor("100"))
-	suite.Equal(COLOR_BLACK, batteryIconColor("10"))
-	suite.Equal(COLOR_RED, batteryIconColor("5"))
-	suite.Equal(COLOR_RED, batteryIconColor("1"))
-	suite.Equal(COLOR_RED, batteryIconColor("4"))
-	suite.Equal(COLOR_RED, batteryIconColor("0"))
-	suite.Equal(COLOR_RED, batteryIconColor("xxx"))
-
-	suite.Equal(BATTERY_LEVEL_0_4, batteryIcon("xxx"))
-	suite.Equal(BATTERY_LEVEL_4_4, batteryIcon("100"))
-	suite.Equal(BATTERY_LEVEL_4_4, batteryIcon("90"))
-	suite.Equal(BATTERY_LEVEL_3_4, batteryIcon("89"))
-	suite.Equal(BATTERY_LEVEL_3_4, batteryIcon("80"))
-	suite.Equal(BATTERY_LEVEL_3_4, batteryIcon("75"))
-	suite.Equal(BATTERY_LEVEL_2_4, batteryIcon("70"))
-	suite.Equal(BATTERY_LEVEL_2_4, batteryIcon("60"))
-	suite.Equal(BATTERY_LEVEL_2_4, batteryIcon("50"))
-	suite.Equal(BATTERY_LEVEL_1_4, batteryIcon("40"))
-	suite.Equal(BATTERY_LEVEL_1_4, batteryIcon("30"))
-	suite.Equal(BATTERY_LEVEL_1_4, batteryIcon("20"))
-	suite.Equal(BATTERY_LEVEL_1_4, batteryIcon("10"))
-	suite.Equal(BATTERY_LEVEL_0_4, batteryIcon("5"))
+	batteryCfg := batteryConfigFromConfig(loadConfigForTest(nil), "hdb.indoorclimate.battery")
+	suite.Equal(BATTERY_SCALE_PERCENT, batteryCfg.scale)
+
+	suite.Equal(COLOR_BLACK, batteryCfg.levelFor("100").color)
+	suite.Equal(COLOR_BLACK, batteryCfg.levelFor("10").color)
+	suite.Equal(COLOR_RED, batteryCfg.levelFor("5").color)
+	suite.Equal(COLOR_RED, batteryCfg.levelFor("1").color)
+	suite.Equal(COLOR_RED, batteryCfg.levelFor("4").color)
+	suite.Equal(COLOR_RED, batteryCfg.levelFor("0").color)
+	suite.Equal(COLOR_RED, batteryCfg.levelFor("xxx").color)
+
+	suite.Equal(BATTERY_LEVEL_0_4, batteryCfg.levelFor("xxx").icon)
+	suite.Equal(BATTERY_LEVEL_4_4, batteryCfg.levelFor("100").icon)
+	suite.Equal(BATTERY_LEVEL_4_4, batteryCfg.levelFor("90").icon)
+	suite.Equal(BATTERY_LEVEL_3_4, batteryCfg.levelFor("89").icon)
+	suite.Equal(BATTERY_LEVEL_3_4, batteryCfg.levelFor("80").icon)
+	suite.Equal(BATTERY_LEVEL_3_4, batteryCfg.levelFor("75").icon)
+	suite.Equal(BATTERY_LEVEL_2_4, batteryCfg.levelFor("70").icon)
+	suite.Equal(BATTERY_LEVEL_2_4, batteryCfg.levelFor("60").icon)
+	suite.Equal(BATTERY_LEVEL_2_4, batteryCfg.levelFor("50").icon)
+	suite.Equal(BATTERY_LEVEL_1_4, batteryCfg.levelFor("40").icon)
+	suite.Equal(BATTERY_LEVEL_1_4, batteryCfg.levelFor("30").icon)
+	suite.Equal(BATTERY_LEVEL_1_4, batteryCfg.levelFor("20").icon)
+	suite.Equal(BATTERY_LEVEL_1_4, batteryCfg.levelFor("10").icon)
+	suite.Equal(BATTERY_LEVEL_0_4, batteryCfg.levelFor("5").icon)
+}
+
+func (suite *UtilsTestSuite) TestBatteryConfig() {
+
+	conf := loadConfigForTest(config.AsStringPtr("fixtures/batterytest01.yml"))
+	batteryCfg := batteryConfigFromConfig(conf, "hdb.indoorclimate.battery")
+	suite.Equal(BATTERY_SCALE_VOLTAGE, batteryCfg.scale)
+	suite.Len(batteryCfg.levels, 3)
+	suite.Equal(60.0, batteryCfg.levels[0].threshold)
+
+	suite.Equal(100.0, batteryCfg.percent("3100"))
+	suite.Equal(50.0, batteryCfg.percent("2900"))
+	suite.Equal(0.0, batteryCfg.percent("2500"))
+	suite.Equal(0.0, batteryCfg.percent("xxx"))
+
+	suite.Equal(batteryLevel{threshold: 60, icon: "\uf240", color: COLOR_BLACK}, batteryCfg.levelFor("3000"))
+	suite.Equal(batteryLevel{threshold: 20, icon: "\uf243", color: COLOR_RED}, batteryCfg.levelFor("2800"))
+	suite.Equal(batteryLevel{threshold: 0, icon: "\uf244", color: COLOR_RED}, batteryCfg.levelFor("2600"))
+	suite.Equal(batteryLevel{threshold: 0, icon: "\uf244", color: COLOR_RED}, batteryCfg.levelFor("xxx"))
+
+	defaultBatteryCfg := batteryConfigFromConfig(conf, "hdb.indoorclimate.xxx")
+	suite.Equal(2500.0, defaultBatteryCfg.voltageMin)
+	suite.Equal(3000.0, defaultBatteryCfg.voltageMax)
+	defaultBatteryCfg.scale = BATTERY_SCALE_VOLTAGE
+	suite.Equal(50.0, defaultBatteryCfg.percent("2750"))
 }
 
 func (suite *UtilsTestSuite) TestConvertDegreesToDirection() {
@@ -141,3 +274,26 @@ func (suite *UtilsTestSuite) TestConvertDegreesToDirection() {
 	suite.Equal("N", degreesToDirection(340))
 	suite.Equal("N/A", degreesToDirection(600))
 }
+
+func (suite *UtilsTestSuite) TestRoomAnchorAndTemplate() {
+
+	conf := loadConfigForTest(config.AsStringPtr("fixtures/testconfig16.yml"))
+	roomCfg := configForRooms(conf, "hdb.indoorclimate")
+	suite.Equal(&core.Point{X: 300, Y: 250}, roomCfg.rooms["1"].anchor)
+	suite.Nil(roomCfg.rooms["2"].anchor)
+	suite.Equal(&core.Point{X: 0, Y: 40}, roomCfg.rooms["3"].anchor)
+	suite.Equal("indoorclimate_tile.json", roomCfg.rooms["1"].template)
+
+	suite.Equal(&core.Point{X: 0, Y: 0}, roomAnchorFromConfig(map[string]string{"anchorX": "-5"}))
+}
+
+func (suite *UtilsTestSuite) TestRoomGroups() {
+
+	conf := loadConfigForTest(config.AsStringPtr("fixtures/testconfig17.yml"))
+	roomCfg := configForRooms(conf, "hdb.indoorclimate")
+	suite.Equal([]roomGroup{roomGroup{id: "upstairs", name: "Upstairs"}, roomGroup{id: "ground", name: "Ground floor"}, roomGroup{id: "outside", name: "outside"}}, roomCfg.groups)
+	suite.Equal("ground", roomCfg.rooms["1"].group)
+	suite.Equal("", roomCfg.rooms["5"].group)
+	suite.True(roomCfg.hasGroup("upstairs"))
+	suite.False(roomCfg.hasGroup(""))
+}
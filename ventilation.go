@@ -0,0 +1,96 @@
+package syncsign
+
+import (
+	"errors"
+	"strconv"
+
+	config "github.com/tommzn/go-config"
+	log "github.com/tommzn/go-log"
+	events "github.com/tommzn/hdb-events-go"
+	core "github.com/tommzn/hdb-renderer-core"
+)
+
+// NewVentilationRenderer returns a renderer which generates ventilation advices for all rooms, using indoor climate
+// and current weather of passed providers.
+func NewVentilationRenderer(conf config.Config, logger log.Logger, template core.Template, indoorClimate IndoorClimateProvider, weather WeatherProvider) *VentilationRenderer {
+	return NewVentilationRendererWithConfigKey(conf, "hdb.ventilation", logger, template, indoorClimate, weather)
+}
+
+// NewVentilationRendererWithConfigKey returns a renderer for ventilation advices which uses settings from passed config key, e.g. "hdb.ventilation".
+func NewVentilationRendererWithConfigKey(conf config.Config, configKey string, logger log.Logger, template core.Template, indoorClimate IndoorClimateProvider, weather WeatherProvider) *VentilationRenderer {
+	return &VentilationRenderer{
+		template:         template,
+		anchor:           anchorFromConfig(conf, configKey+".anchor"),
+		size:             sizeFromConfig(conf, configKey+".size"),
+		logger:           logger,
+		indoorClimate:    indoorClimate,
+		weather:          weather,
+		minTemperature:   floatFromConfig(conf, configKey+".min_temperature", 20),
+		temperatureDelta: floatFromConfig(conf, configKey+".temperature_delta", 1),
+		unit:             temperatureUnitFromConfig(conf, configKey+".unit"),
+		precision:        precisionFromConfig(conf, configKey+".precision").temperature,
+	}
+}
+
+// Content generates a ventilation advice for each room. Rooms are placed from top to bottom.
+func (renderer *VentilationRenderer) Content() (string, error) {
+
+	defer renderer.logger.Flush()
+
+	currentWeather := renderer.weather.CurrentWeather()
+	if currentWeather == nil {
+		return "", errors.New("No current weather available.")
+	}
+
+	content := ""
+	anchor := renderer.anchor
+	for idx, roomClimate := range renderer.indoorClimate.RoomClimate() {
+		advice := renderer.adviceFor(roomClimate, currentWeather)
+		advice.Anchor = anchor
+		advice.DisplayIndex = idx
+		elementContent, err := renderer.template.RenderWith(advice)
+		if err != nil {
+			return content, err
+		}
+		content += elementContent
+		anchor.Y += renderer.size.Height
+	}
+	return content, nil
+}
+
+// adviceFor compares indoor climate of passed room with current weather. Outdoor air is cooler if it's temperature
+// is at least temperature delta below room temperature. Because weather data doesn't contain humidity, outdoor air
+// is drier only if it's absolute humidity at saturation is below absolute humidity of a room. Windows should be opened
+// if outdoor air is drier, or if it's cooler and room temperature is at or above min temperature.
+func (renderer *VentilationRenderer) adviceFor(roomClimate RoomClimate, currentWeather *events.CurrentWeather) ventilationAdvice {
+
+	advice := ventilationAdvice{
+		RoomName:           roomClimate.RoomName,
+		IndoorTemperature:  "--",
+		OutdoorTemperature: renderer.formatTemperature(currentWeather.Temperature),
+		Advice:             VENTILATION_UNKNOWN,
+		AdviceIcon:         VENTILATION_ICON_UNKNOWN,
+	}
+	if roomClimate.Temperature == nil || roomClimate.Stale {
+		return advice
+	}
+
+	indoorTemperature := *roomClimate.Temperature
+	advice.IndoorTemperature = renderer.formatTemperature(indoorTemperature)
+	advice.Cooler = currentWeather.Temperature <= indoorTemperature-renderer.temperatureDelta
+	if roomClimate.Humidity != nil {
+		advice.Drier = absoluteHumidity(currentWeather.Temperature, 100) < absoluteHumidity(indoorTemperature, *roomClimate.Humidity)
+	}
+
+	if advice.Drier || (advice.Cooler && indoorTemperature >= renderer.minTemperature) {
+		advice.Advice, advice.AdviceIcon = VENTILATION_OPEN, VENTILATION_ICON_OPEN
+	} else {
+		advice.Advice, advice.AdviceIcon = VENTILATION_CLOSED, VENTILATION_ICON_CLOSED
+	}
+	return advice
+}
+
+// formatTemperature formats passed temperature in celsius with unit and precision of this renderer.
+func (renderer *VentilationRenderer) formatTemperature(temperature float64) string {
+	return formatTemperature(strconv.FormatFloat(temperature, 'f', -1, 64), renderer.unit, renderer.precision)
+}
@@ -0,0 +1,102 @@
+package syncsign
+
+import (
+	"github.com/stretchr/testify/suite"
+	"testing"
+
+	config "github.com/tommzn/go-config"
+	events "github.com/tommzn/hdb-events-go"
+)
+
+type VentilationTestSuite struct {
+	suite.Suite
+}
+
+func TestVentilationTestSuite(t *testing.T) {
+	suite.Run(t, new(VentilationTestSuite))
+}
+
+func (suite *VentilationTestSuite) TestGenerateContent() {
+
+	renderer := ventilationRendererForTest(indoorClimateRendererForTest("fixtures/testconfig02.yml"), weatherRendererForTest("fixtures/testconfig.yml"))
+
+	items, err := itemsFromRenderer(renderer)
+	suite.Nil(err)
+	suite.Len(items, 4)
+	suite.Equal("Room1", items[0].Data.(*TextData).Text)
+	suite.Equal(string(VENTILATION_ICON_CLOSED), items[1].Data.(*TextData).Text)
+	suite.Equal("Room2", items[2].Data.(*TextData).Text)
+	suite.Equal(string(VENTILATION_ICON_OPEN), items[3].Data.(*TextData).Text)
+	suite.Equal(40, items[2].Data.(*TextData).Block.Y)
+}
+
+func (suite *VentilationTestSuite) TestGenerateContentWithoutWeather() {
+
+	renderer := ventilationRendererForTest(indoorClimateRendererForTest("fixtures/testconfig12.yml"), newWeatherProviderMock(nil))
+
+	content, err := renderer.Content()
+	suite.NotNil(err)
+	suite.Equal("", content)
+}
+
+func (suite *VentilationTestSuite) TestAdvice() {
+
+	renderer := ventilationRendererForTest(indoorClimateRendererForTest("fixtures/testconfig12.yml"), newWeatherProviderMock(nil))
+
+	warmWeather := &events.CurrentWeather{Temperature: 21.7}
+	coldWeather := &events.CurrentWeather{Temperature: 5}
+
+	advice1 := renderer.adviceFor(RoomClimate{RoomName: "Room1", Temperature: floatPtr(17.1), Humidity: floatPtr(65)}, warmWeather)
+	suite.False(advice1.Cooler)
+	suite.False(advice1.Drier)
+	suite.Equal(VENTILATION_CLOSED, advice1.Advice)
+	suite.Equal("17.1", advice1.IndoorTemperature)
+	suite.Equal("21.7", advice1.OutdoorTemperature)
+
+	advice2 := renderer.adviceFor(RoomClimate{RoomName: "Room1", Temperature: floatPtr(17.1), Humidity: floatPtr(65)}, coldWeather)
+	suite.True(advice2.Cooler)
+	suite.True(advice2.Drier)
+	suite.Equal(VENTILATION_OPEN, advice2.Advice)
+
+	advice3 := renderer.adviceFor(RoomClimate{RoomName: "Room1", Temperature: floatPtr(17.1), Humidity: floatPtr(35)}, coldWeather)
+	suite.True(advice3.Cooler)
+	suite.False(advice3.Drier)
+	suite.Equal(VENTILATION_CLOSED, advice3.Advice)
+
+	advice4 := renderer.adviceFor(RoomClimate{RoomName: "Room1", Temperature: floatPtr(25)}, &events.CurrentWeather{Temperature: 24.5})
+	suite.False(advice4.Cooler)
+	suite.Equal(VENTILATION_CLOSED, advice4.Advice)
+
+	advice5 := renderer.adviceFor(RoomClimate{RoomName: "Room1", Temperature: floatPtr(25)}, &events.CurrentWeather{Temperature: 20})
+	suite.True(advice5.Cooler)
+	suite.Equal(VENTILATION_OPEN, advice5.Advice)
+
+	advice6 := renderer.adviceFor(RoomClimate{RoomName: "Room1", Temperature: floatPtr(25), Stale: true}, coldWeather)
+	suite.Equal(VENTILATION_UNKNOWN, advice6.Advice)
+	suite.Equal(VENTILATION_ICON_UNKNOWN, advice6.AdviceIcon)
+	suite.Equal("--", advice6.IndoorTemperature)
+
+	advice7 := renderer.adviceFor(RoomClimate{RoomName: "Room1"}, coldWeather)
+	suite.Equal(VENTILATION_UNKNOWN, advice7.Advice)
+}
+
+func (suite *VentilationTestSuite) TestConfig() {
+
+	conf := loadConfigForTest(config.AsStringPtr("fixtures/testconfig14.yml"))
+	renderer := NewVentilationRenderer(conf, loggerForTest(), templateForTest(), indoorClimateRendererForTest("fixtures/testconfig12.yml"), newWeatherProviderMock(nil))
+	suite.Equal(22.0, renderer.minTemperature)
+	suite.Equal(1.5, renderer.temperatureDelta)
+	suite.Equal(10, renderer.anchor.X)
+	suite.Equal(40, renderer.size.Height)
+}
+
+func (suite *VentilationTestSuite) TestTemperatureUnitAndPrecision() {
+
+	conf := loadConfigForTest(config.AsStringPtr("fixtures/testconfig22.yml"))
+	renderer := NewVentilationRenderer(conf, loggerForTest(), templateForTest(), indoorClimateRendererForTest("fixtures/testconfig12.yml"), newWeatherProviderMock(nil))
+
+	advice := renderer.adviceFor(RoomClimate{RoomName: "Room1", Temperature: floatPtr(21.2)}, &events.CurrentWeather{Temperature: 5})
+	suite.Equal("70", advice.IndoorTemperature)
+	suite.Equal("41", advice.OutdoorTemperature)
+	suite.Equal(VENTILATION_OPEN, advice.Advice)
+}
@@ -14,11 +14,16 @@ import (
 
 // NewWeatherRenderer returns a renderer which generates items for current weather and forcast.
 func NewWeatherRenderer(conf config.Config, logger log.Logger, currentWeatherTemplate core.Template, forecastTemplate core.Template, datasource core.DataSource) *WeatherRenderer {
+	return NewWeatherRendererWithConfigKey(conf, "hdb.weather", logger, currentWeatherTemplate, forecastTemplate, datasource)
+}
+
+// NewWeatherRendererWithConfigKey returns a renderer for current weather and forcast which uses settings from passed config key, e.g. "hdb.weather".
+func NewWeatherRendererWithConfigKey(conf config.Config, configKey string, logger log.Logger, currentWeatherTemplate core.Template, forecastTemplate core.Template, datasource core.DataSource) *WeatherRenderer {
 
-	anchor := anchorFromConfig(conf, "hdb.weather.anchor")
-	currentWeatherSize := sizeFromConfig(conf, "hdb.weather.current.size")
-	forecastWeatherSize := sizeFromConfig(conf, "hdb.weather.forecast.size")
-	forecastLimit := conf.GetAsInt("hdb.weather.forecast.limit", config.AsIntPtr(6))
+	anchor := anchorFromConfig(conf, configKey+".anchor")
+	currentWeatherSize := sizeFromConfig(conf, configKey+".current.size")
+	forecastWeatherSize := sizeFromConfig(conf, configKey+".forecast.size")
+	forecastLimit := conf.GetAsInt(configKey+".forecast.limit", config.AsIntPtr(6))
 	return &WeatherRenderer{
 		currentWeatherTemplate: currentWeatherTemplate,
 		forecastTemplate:       forecastTemplate,
@@ -35,19 +40,21 @@ func NewWeatherRenderer(conf config.Config, logger log.Logger, currentWeatherTem
 // Content generates items for weather data.
 func (renderer *WeatherRenderer) Content() (string, error) {
 
-	if renderer.weatherData == nil {
+	weatherData := renderer.latestWeatherData()
+	if weatherData == nil {
 		if err := renderer.fetchEvents(); err != nil {
 			renderer.logger.Errorf("Unable to get weather data, reason: %s", err)
 			return "", err
 		}
+		weatherData = renderer.latestWeatherData()
 	}
 
-	content, err := renderer.currentWeatherTemplate.RenderWith(renderer.currentWeatherData())
+	content, err := renderer.currentWeatherTemplate.RenderWith(renderer.currentWeatherData(weatherData))
 	if err != nil {
 		return content, err
 	}
 
-	forecastData := renderer.forecastWeatherData()
+	forecastData := renderer.forecastWeatherData(weatherData)
 	for _, forecast := range forecastData {
 		forecastContent, err := renderer.forecastTemplate.RenderWith(forecast)
 		if err != nil {
@@ -74,10 +81,13 @@ func (renderer *WeatherRenderer) ObserveDataSource(ctx context.Context) {
 	defer renderer.logger.Flush()
 
 	filter := []hdbcore.DataSource{hdbcore.DATASOURCE_WEATHER}
-	renderer.dataSourceChan = renderer.datasource.Observe(&filter)
+	dataSourceChan := renderer.datasource.Observe(&filter)
+	renderer.lock.Lock()
+	renderer.dataSourceChan = dataSourceChan
+	renderer.lock.Unlock()
 	for {
 		select {
-		case message, ok := <-renderer.dataSourceChan:
+		case message, ok := <-dataSourceChan:
 			if !ok {
 				renderer.logger.Error("Error at reading datasource channel. Stop observing!")
 				return
@@ -95,29 +105,58 @@ func (renderer *WeatherRenderer) processEvent(message proto.Message) {
 
 	if weatherData, ok := message.(*events.WeatherData); ok {
 		renderer.logger.Debug("Receive new weather data")
+		renderer.lock.Lock()
 		renderer.weatherData = weatherData
+		renderer.lock.Unlock()
+	}
+}
+
+// LatestWeatherData returns latest received weather data, nil if nothing has been received, yet.
+// Received weather data will not be changed, it's replaced by new events, only.
+func (renderer *WeatherRenderer) latestWeatherData() *events.WeatherData {
+
+	renderer.lock.RLock()
+	defer renderer.lock.RUnlock()
+
+	return renderer.weatherData
+}
+
+// CurrentWeather returns latest current weather, nil if there's no weather data.
+func (renderer *WeatherRenderer) CurrentWeather() *events.CurrentWeather {
+
+	weatherData := renderer.latestWeatherData()
+	if weatherData == nil {
+		if err := renderer.fetchEvents(); err != nil {
+			renderer.logger.Errorf("Unable to get weather data, reason: %s", err)
+			return nil
+		}
+		weatherData = renderer.latestWeatherData()
+	}
+	if weatherData == nil {
+		return nil
 	}
+	return weatherData.Current
 }
 
-func (renderer *WeatherRenderer) currentWeatherData() weatherData {
+func (renderer *WeatherRenderer) currentWeatherData(weather *events.WeatherData) weatherData {
 	return weatherData{
 		Anchor:        renderer.anchor,
-		WeatherIcon:   renderer.weatherIconMap.toWeatherIcon(renderer.weatherData.Current.Weather.Icon),
-		Temperature:   fmt.Sprintf("%.1f", renderer.weatherData.Current.Temperature),
-		WindSpeed:     formatWindSpeed(renderer.weatherData.Current),
-		WindDirection: degreesToDirection(renderer.weatherData.Current.WindDirection),
-		Day:           renderer.weatherData.Current.Timestamp.AsTime().Format("Monday"),
+		WeatherIcon:   renderer.weatherIconMap.toWeatherIcon(weather.Current.Weather.Icon),
+		Temperature:   fmt.Sprintf("%.1f", weather.Current.Temperature),
+		WindSpeed:     formatWindSpeed(weather.Current),
+		WindDirection: degreesToDirection(weather.Current.WindDirection),
+		Day:           weather.Current.Timestamp.AsTime().Format("Monday"),
 		DisplayIndex:  0,
 	}
 }
 
-func (renderer *WeatherRenderer) forecastWeatherData() []weatherData {
+func (renderer *WeatherRenderer) forecastWeatherData(weather *events.WeatherData) []weatherData {
 
 	displayIndex := 0
 	anchor := renderer.anchor
 	anchor.Y += renderer.currentWeatherSize.Height
 	forecasts := []weatherData{}
-	for _, forecast := range renderer.weatherData.Forecast {
+	for _, forecast := range weather.Forecast {
 		forecasts = append(forecasts, weatherData{
 			Anchor:           anchor,
 			WeatherIcon:      renderer.weatherIconMap.toWeatherIcon(forecast.Weather.Icon),
@@ -1,7 +1,9 @@
 package syncsign
 
 import (
+	"context"
 	"github.com/stretchr/testify/suite"
+	"sync"
 	"testing"
 
 	events "github.com/tommzn/hdb-events-go"
@@ -35,3 +37,41 @@ func (suite *WeatherTestSuite) TestFormatWindSpeed() {
 	weatherData.Current.WindGust = 32
 	suite.Equal("7/32", formatWindSpeed(weatherData.Current))
 }
+
+func (suite *WeatherTestSuite) TestConcurrentObserveAndRender() {
+
+	renderer := weatherRendererForTest("fixtures/testconfig06.yml")
+	ctx, cancelFunc := context.WithCancel(context.Background())
+	defer cancelFunc()
+	go renderer.ObserveDataSource(ctx)
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				_, err := renderer.Content()
+				suite.Nil(err)
+			}
+		}()
+	}
+	for i := 0; i < 10; i++ {
+		for _, message := range weatherDataForTest() {
+			renderer.datasource.(*datasourceMock).writeToMessageChannel(message)
+		}
+	}
+	wg.Wait()
+}
+
+func (suite *WeatherTestSuite) TestShareCurrentWeather() {
+
+	renderer := weatherRendererForTest("fixtures/testconfig06.yml")
+	currentWeather := renderer.CurrentWeather()
+	suite.NotNil(currentWeather)
+	suite.Equal(21.7, currentWeather.Temperature)
+
+	renderer.datasource = newDataSourceMock(true, false, fixturesForWeatherRenderer())
+	renderer.weatherData = nil
+	suite.Nil(renderer.CurrentWeather())
+}